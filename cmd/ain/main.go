@@ -8,11 +8,16 @@ import (
 	"runtime"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/jonaslu/ain/internal/app/ain"
 	"github.com/jonaslu/ain/internal/pkg/call"
+	"github.com/jonaslu/ain/internal/pkg/data"
 	"github.com/jonaslu/ain/internal/pkg/disk"
+	"github.com/jonaslu/ain/internal/pkg/oauth2"
 	"github.com/jonaslu/ain/internal/pkg/parse"
+	"github.com/jonaslu/ain/internal/pkg/sign"
+	"github.com/jonaslu/ain/internal/pkg/utils"
 )
 
 var version = "1.6.0"
@@ -39,11 +44,52 @@ func checkSignalRaisedAndExit(ctx context.Context, signalRaised os.Signal) {
 func main() {
 	cmdParams := ain.NewCmdParams()
 
+	if cmdParams.Import != nil {
+		if err := ain.Import(cmdParams.Import); err != nil {
+			printErrorAndExit(err)
+		}
+
+		return
+	}
+
+	if cmdParams.Export != nil {
+		if err := ain.Export(cmdParams.Export, version); err != nil {
+			printErrorAndExit(err)
+		}
+
+		return
+	}
+
 	if cmdParams.ShowVersion {
 		fmt.Printf("Ain %s (%s) %s/%s\n", version, gitSha, runtime.GOOS, runtime.GOARCH)
 		return
 	}
 
+	outputJSON := cmdParams.OutputFormat == call.OutputFormatJSON
+	if cmdParams.OutputFormat != "" && !outputJSON {
+		printErrorAndExit(fmt.Errorf("unknown --output format: %s, valid formats are: %s", cmdParams.OutputFormat, call.OutputFormatJSON))
+	}
+
+	if cmdParams.InlineBody && !cmdParams.PrintCommand {
+		printErrorAndExit(fmt.Errorf("--inline-body only applies when printing the command with -p"))
+	}
+
+	if cmdParams.Shell != "" && !cmdParams.PrintCommand {
+		printErrorAndExit(fmt.Errorf("--shell only applies when printing the command with -p"))
+	}
+
+	if _, exists := utils.Shells[cmdParams.Shell]; cmdParams.Shell != "" && !exists {
+		printErrorAndExit(fmt.Errorf("unknown --shell: %s, valid shells are: %s", cmdParams.Shell, strings.Join(utils.ShellNames(), ", ")))
+	}
+
+	if cmdParams.AsBackend != "" && !call.ValidBackend(cmdParams.AsBackend) {
+		printErrorAndExit(fmt.Errorf("unknown --as backend: %s, valid backends are: %s", cmdParams.AsBackend, strings.Join(call.BackendNames(), ", ")))
+	}
+
+	if cmdParams.PrintAs != "" && !call.ValidPrinter(cmdParams.PrintAs) {
+		printErrorAndExit(fmt.Errorf("unknown --print-as language: %s, valid languages are: %s", cmdParams.PrintAs, strings.Join(call.PrinterNames(), ", ")))
+	}
+
 	if err := cmdParams.SetEnvVarsAndFilenames(); err != nil {
 		printErrorAndExit(err)
 	}
@@ -66,15 +112,55 @@ func main() {
 		printErrorAndExit(err)
 	}
 
-	localTemplateFileNames, err := disk.GetTemplateFilenames(cmdParams.TemplateFileNames)
-	if err != nil {
-		printErrorAndExit(err)
+	localTemplateFileNames := cmdParams.TemplateFileNames
+	if cmdParams.BodyFromStdin {
+		stdinIsPipe, err := disk.StdinIsPipe()
+		if err != nil {
+			printErrorAndExit(err)
+		}
+
+		if !stdinIsPipe {
+			printErrorAndExit(fmt.Errorf("--body-stdin passed but ain is not connected to a pipe"))
+		}
+
+		for _, templateFileName := range localTemplateFileNames {
+			if disk.IsStdinTemplateName(templateFileName) {
+				printErrorAndExit(fmt.Errorf("cannot read both the body (--body-stdin) and a template (-) from stdin"))
+			}
+		}
+	} else {
+		var err error
+		localTemplateFileNames, err = disk.GetTemplateFilenames(cmdParams.TemplateFileNames)
+		if err != nil {
+			printErrorAndExit(err)
+		}
 	}
 
 	if len(localTemplateFileNames) == 0 {
 		printErrorAndExit(fmt.Errorf("missing template file name(s)\n\nTry 'ain -h' for more information"))
 	}
 
+	if cmdParams.ListRequests {
+		requestFileNames, err := parse.ListRequests(localTemplateFileNames)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+
+		for _, requestFileName := range requestFileNames {
+			fmt.Fprintln(os.Stdout, requestFileName)
+		}
+
+		return
+	}
+
+	if cmdParams.DiscoverBaseTemplates {
+		var err error
+		localTemplateFileNames, err = disk.AddBaseTemplateFilenames(localTemplateFileNames)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+	}
+
 	cancelCtx, cancel := context.WithCancel(context.Background())
 	var signalRaised os.Signal
 
@@ -86,7 +172,8 @@ func main() {
 		cancel()
 	}()
 
-	assembledCtx, backendInput, fatal, err := parse.Assemble(cancelCtx, localTemplateFileNames)
+	assembledCtx, assembledCancel, backendInput, fatal, err := parse.Assemble(cancelCtx, localTemplateFileNames, cmdParams.AsBackend)
+	defer assembledCancel()
 	if err != nil {
 		checkSignalRaisedAndExit(assembledCtx, signalRaised)
 
@@ -101,7 +188,65 @@ func main() {
 		os.Exit(1)
 	}
 
+	if unsupportedConfig := call.UnsupportedConfig(backendInput); len(unsupportedConfig) > 0 {
+		fmt.Fprintln(os.Stderr, strings.Join(unsupportedConfig, "\n"))
+		os.Exit(1)
+	}
+
+	call.TranslateBackendOptions(backendInput)
+	if len(backendInput.LeftOutBackendOptions) > 0 {
+		fmt.Fprintf(os.Stderr, "Could not translate [BackendOptions] to %s, left out:\n", backendInput.Backend)
+		for _, leftOutBackendOption := range backendInput.LeftOutBackendOptions {
+			fmt.Fprintf(os.Stderr, "  %s\n", leftOutBackendOption)
+		}
+	}
+
+	if backendInput.OAuth2 != nil {
+		// The token is masked when printed, so it's only fetched for a call
+		accessToken := oauth2.PrintedAccessToken
+		if !cmdParams.PrintCommand && cmdParams.PrintAs == "" {
+			accessToken, err = oauth2.GetAccessToken(assembledCtx, *backendInput.OAuth2, backendInput.Config)
+			if err != nil {
+				checkSignalRaisedAndExit(assembledCtx, signalRaised)
+
+				printErrorAndExit(err)
+			}
+		}
+
+		backendInput.Auth = &data.Auth{Scheme: data.AuthBearer, Token: accessToken}
+	}
+
 	backendInput.PrintCommand = cmdParams.PrintCommand
+	backendInput.InlineBody = cmdParams.InlineBody
+	backendInput.PrintShell = cmdParams.Shell
+	backendInput.InspectResponse = cmdParams.StatusExitCode || outputJSON
+
+	if cmdParams.BodyFromStdin {
+		// The piped body overwrites any [Body] in the templates
+		backendInput.Body = nil
+		backendInput.BodyFromStdin = true
+	}
+
+	// Before [Sign] adds its headers, they're in the warning as [Sign]
+	httpieSessionWarning := call.HttpieSessionWarning(backendInput)
+
+	if err := sign.SignRequest(backendInput, time.Now()); err != nil {
+		printErrorAndExit(err)
+	}
+
+	if cmdParams.PrintAs != "" {
+		code, err := call.PrintAs(backendInput, cmdParams.PrintAs)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+
+		fmt.Fprint(os.Stdout, code)
+		return
+	}
+
+	if httpieSessionWarning != "" {
+		fmt.Fprintln(os.Stderr, httpieSessionWarning)
+	}
 
 	call, err := call.Setup(backendInput)
 	if err != nil {
@@ -143,7 +288,17 @@ func main() {
 		// It's customary to print stderr first
 		// to get the users attention on the error
 		fmt.Fprint(os.Stderr, backendOutput.Stderr)
-		fmt.Fprint(os.Stdout, backendOutput.Stdout)
+
+		if outputJSON {
+			jsonOutput, err := call.OutputAsJSON(backendOutput)
+			if err != nil {
+				printErrorAndExit(err)
+			}
+
+			fmt.Fprint(os.Stdout, jsonOutput)
+		} else {
+			fmt.Fprint(os.Stdout, backendOutput.Stdout)
+		}
 	}
 
 	checkSignalRaisedAndExit(assembledCtx, signalRaised)
@@ -152,5 +307,9 @@ func main() {
 		os.Exit(1)
 	}
 
+	if cmdParams.StatusExitCode {
+		os.Exit(backendOutput.StatusExitCode())
+	}
+
 	os.Exit(backendOutput.ExitCode)
 }
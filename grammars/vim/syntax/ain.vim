@@ -4,7 +4,7 @@ if exists("b:current_syntax")
 endif
 
 " Headings
-syntax match ainHeading /^\s*\[\(config\|host\|query\|headers\|method\|body\|backend\|backendoptions\)\]\s*\ze\(\s*#\|\s*$\)\c/
+syntax match ainHeading /^\s*\[\(config\|host\|query\|headers\|method\|body\|backend\|backendoptions\|include\|auth\|oauth2\|sign\|cookies\)\]\s*\ze\(\s*#\|\s*$\)\c/
 highlight link ainHeading Keyword
 
 " Escapes
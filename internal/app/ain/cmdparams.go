@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"strings"
+
+	"github.com/jonaslu/ain/internal/pkg/disk"
 )
 
 const varsFlagStr = "--vars"
@@ -23,8 +25,13 @@ Project home page: https://github.com/jonaslu/ain`
 	}
 
 	fmt.Fprintf(w, "\nARGUMENTS:\n")
-	fmt.Fprintf(w, "  <template.ain>[!]       One or more template files to process. Required\n")
-	fmt.Fprintf(w, "  "+varsFlagStr+" VAR=VALUE [...]  Values for environment variables, set after <template.ain> file(s)\n")
+	fmt.Fprintf(w, "  <template.ain>[#name][!] One or more template files to process. Required\n")
+	fmt.Fprintf(w, "  -[#name][!]              Read template contents from a pipe instead of a file\n")
+	fmt.Fprintf(w, "  "+varsFlagStr+" VAR=VALUE [...]   Values for environment variables, set after <template.ain> file(s)\n")
+
+	fmt.Fprintf(w, "\nCOMMANDS:\n")
+	fmt.Fprintf(w, "  %-22s %s\n", importCommandStr, "Import requests from other tools into templates, see '"+appName+" "+importCommandStr+" -h'")
+	fmt.Fprintf(w, "  %-22s %s\n", exportCommandStr, "Export templates as requests for other tools, see '"+appName+" "+exportCommandStr+" -h'")
 }
 
 type flagConsumer func([]string) (found bool, restArgs []string, error error)
@@ -93,22 +100,7 @@ func makeStringFlag(flagName, usage string, val *string) flag {
 	return makeFlag(flagName, usage, makeRedefinedGuardConsumer(flagName, makeStringConsumer(flagName, val)))
 }
 
-func NewCmdParams() *CmdParams {
-	var leaveTmpFile, printCommand, showVersion, generateEmptyTemplate, showHelp bool
-	envFile := ".env"
-
-	flags := []flag{}
-
-	appName := os.Args[0]
-	restArgs := os.Args[1:]
-
-	flags = append(flags, makeBoolFlag("-p", "Print command to the terminal instead of executing", &printCommand))
-	flags = append(flags, makeStringFlag("-e", "Path to .env file", &envFile))
-	flags = append(flags, makeBoolFlag("-l", "Leave any body-files", &leaveTmpFile))
-	flags = append(flags, makeBoolFlag("-b", "Generate basic template files(s)", &generateEmptyTemplate))
-	flags = append(flags, makeBoolFlag("-v", "Show version and exit", &showVersion))
-	flags = append(flags, makeBoolFlag("-h", "Show help and exit", &showHelp))
-
+func parseFlags(appName string, flags []flag, restArgs []string) []string {
 	for {
 		if len(restArgs) == 0 {
 			break
@@ -120,7 +112,8 @@ func NewCmdParams() *CmdParams {
 			break
 		}
 
-		if strings.HasPrefix(arg, "-") {
+		// A dash (with any #name or !) is the template read from stdin
+		if strings.HasPrefix(arg, "-") && !disk.IsStdinTemplateName(arg) {
 			flagFound := false
 
 			for _, flag := range flags {
@@ -149,6 +142,48 @@ func NewCmdParams() *CmdParams {
 		break
 	}
 
+	return restArgs
+}
+
+func NewCmdParams() *CmdParams {
+	var leaveTmpFile, printCommand, inlineBody, showVersion, generateEmptyTemplate, showHelp, bodyFromStdin, discoverBaseTemplates, listRequests, statusExitCode bool
+	envFile := ".env"
+	outputFormat := ""
+	printAs := ""
+	shell := ""
+	asBackend := ""
+
+	flags := []flag{}
+
+	appName := os.Args[0]
+	restArgs := os.Args[1:]
+
+	if len(restArgs) > 0 && restArgs[0] == importCommandStr {
+		return &CmdParams{Import: newImportParams(appName, restArgs[1:])}
+	}
+
+	if len(restArgs) > 0 && restArgs[0] == exportCommandStr {
+		return &CmdParams{Export: newExportParams(appName, restArgs[1:])}
+	}
+
+	flags = append(flags, makeBoolFlag("-p", "Print command to the terminal instead of executing", &printCommand))
+	flags = append(flags, makeBoolFlag("--inline-body", "Put the body in the printed command instead of a file (with -p)", &inlineBody))
+	flags = append(flags, makeStringFlag("--shell", "Quote the printed command for sh, bash, zsh, fish, powershell (7.3+) or cmd (with -p)", &shell))
+	flags = append(flags, makeStringFlag("--as", "Use this backend instead of the [Backend] (curl, httpie, wget)", &asBackend))
+	flags = append(flags, makeStringFlag("--print-as", "Print the request as code instead of executing (go, python, javascript)", &printAs))
+	flags = append(flags, makeStringFlag("-e", "Path to .env file", &envFile))
+	flags = append(flags, makeBoolFlag("-l", "Leave any body-files", &leaveTmpFile))
+	flags = append(flags, makeBoolFlag("-b", "Generate basic template files(s)", &generateEmptyTemplate))
+	flags = append(flags, makeBoolFlag("--base", "Prepend any _base.ain or .ain-defaults in parent dirs", &discoverBaseTemplates))
+	flags = append(flags, makeBoolFlag("--body-stdin", "Read the [Body] from a pipe instead of template names", &bodyFromStdin))
+	flags = append(flags, makeBoolFlag("--list", "List the named requests in the template file(s) and exit", &listRequests))
+	flags = append(flags, makeBoolFlag("--status-exit", "Exit with 4 on a 4xx, 5 on a 5xx and 3 on no HTTP response", &statusExitCode))
+	flags = append(flags, makeStringFlag("--output", "Print the response in this format (json)", &outputFormat))
+	flags = append(flags, makeBoolFlag("-v", "Show version and exit", &showVersion))
+	flags = append(flags, makeBoolFlag("-h", "Show help and exit", &showHelp))
+
+	restArgs = parseFlags(appName, flags, restArgs)
+
 	if showHelp {
 		printUsage(appName, flags)
 		os.Exit(0)
@@ -158,9 +193,18 @@ func NewCmdParams() *CmdParams {
 		restArgs:              restArgs,
 		LeaveTmpFile:          leaveTmpFile,
 		PrintCommand:          printCommand,
+		InlineBody:            inlineBody,
+		Shell:                 shell,
+		AsBackend:             asBackend,
 		ShowVersion:           showVersion,
 		GenerateEmptyTemplate: generateEmptyTemplate,
+		BodyFromStdin:         bodyFromStdin,
+		DiscoverBaseTemplates: discoverBaseTemplates,
+		ListRequests:          listRequests,
+		StatusExitCode:        statusExitCode,
 		EnvFile:               envFile,
+		OutputFormat:          outputFormat,
+		PrintAs:               printAs,
 	}
 }
 
@@ -201,9 +245,23 @@ type CmdParams struct {
 
 	LeaveTmpFile          bool
 	PrintCommand          bool
+	InlineBody            bool
+	Shell                 string
+	AsBackend             string
 	ShowVersion           bool
 	GenerateEmptyTemplate bool
+	BodyFromStdin         bool
+	DiscoverBaseTemplates bool
+	ListRequests          bool
+	StatusExitCode        bool
 	EnvFile               string
+	OutputFormat          string
+	PrintAs               string
 	EnvVars               [][]string
 	TemplateFileNames     []string
+
+	// Set when running ain import
+	Import *ImportParams
+	// Set when running ain export
+	Export *ExportParams
 }
@@ -0,0 +1,413 @@
+package ain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jonaslu/ain/internal/pkg/call"
+	"github.com/jonaslu/ain/internal/pkg/convert"
+	"github.com/jonaslu/ain/internal/pkg/data"
+	"github.com/jonaslu/ain/internal/pkg/disk"
+	"github.com/jonaslu/ain/internal/pkg/oauth2"
+	"github.com/jonaslu/ain/internal/pkg/parse"
+	"github.com/jonaslu/ain/internal/pkg/sign"
+	"github.com/pkg/errors"
+)
+
+const exportCommandStr = "export"
+
+const (
+	exportFormatHttp       = "http"
+	exportFormatHAR        = "har"
+	exportFormatCurlConfig = "curl-config"
+	exportFormatWgetrc     = "wgetrc"
+)
+
+var exportFormats = []string{exportFormatHttp, exportFormatHAR, exportFormatCurlConfig, exportFormatWgetrc}
+
+type ExportParams struct {
+	Format       string
+	EnvFile      string
+	CallRequests bool
+	// Where the config files and bodies are written
+	Dir string
+	// Template file names, each exported as a request
+	TemplateFileNames []string
+	// Export prints the usage on -h or a missing format
+	showHelp   bool
+	printUsage func()
+}
+
+func printExportUsage(appName string, flags []flag) {
+	w := os.Stderr
+
+	fmt.Fprintf(w, "Exports templates as requests for other tools, printed to stdout or written to --dir.\n\n")
+	fmt.Fprintf(w, "usage: %s %s [OPTIONS] <format> <template.ain>[#name]...\n", appName, exportCommandStr)
+	fmt.Fprintf(w, "\nOPTIONS:\n")
+	for _, f := range flags {
+		fmt.Fprintf(w, "  %-22s %s\n", f.flagName, f.usage)
+	}
+
+	fmt.Fprintf(w, "\nFORMATS:\n")
+	fmt.Fprintf(w, "  %-22s %s\n", exportFormatHttp, "A .http file (VS Code REST Client and JetBrains HTTP client) with a request per template")
+	fmt.Fprintf(w, "  %-22s %s\n", exportFormatHAR, "A HAR 1.2 archive with an entry per template, with the responses when passing --call")
+	fmt.Fprintf(w, "  %-22s %s\n", exportFormatCurlConfig, "A curl config file (curl -K) and any body per template, written to --dir")
+	fmt.Fprintf(w, "  %-22s %s\n", exportFormatWgetrc, "A wgetrc file (wget --config) and any body per template, written to --dir")
+}
+
+func newExportParams(appName string, args []string) *ExportParams {
+	var showHelp, callRequests bool
+	envFile := ".env"
+	dir := ""
+
+	flags := []flag{}
+	flags = append(flags, makeStringFlag("-e", "Path to .env file (har, curl-config, wgetrc)", &envFile))
+	flags = append(flags, makeBoolFlag("--call", "Call the requests and export the responses too (har)", &callRequests))
+	flags = append(flags, makeStringFlag("--dir", "Directory to write the files to (curl-config, wgetrc)", &dir))
+	flags = append(flags, makeBoolFlag("-h", "Show help and exit", &showHelp))
+
+	restArgs := parseFlags(appName, flags, args)
+
+	exportParams := &ExportParams{
+		EnvFile:      envFile,
+		CallRequests: callRequests,
+		Dir:          dir,
+		showHelp:     showHelp,
+		printUsage:   func() { printExportUsage(appName, flags) },
+	}
+
+	if len(restArgs) > 0 {
+		exportParams.Format = restArgs[0]
+		exportParams.TemplateFileNames = restArgs[1:]
+	}
+
+	return exportParams
+}
+
+// getExportRequestFileNames returns a template file name per request.
+// As when running ain, a file is only split into requests when one is
+// selected with #name, otherwise any ### lines are comments.
+func getExportRequestFileNames(templateFileNames []string) ([]string, error) {
+	if len(templateFileNames) == 0 {
+		return nil, errors.New("missing template file name(s) to export")
+	}
+
+	return templateFileNames, nil
+}
+
+// getExportRequestName names the request after the
+// template file or the selected request in it
+func getExportRequestName(requestFileName string) string {
+	templateFileName, requestName := disk.SplitRequestName(requestFileName)
+	if requestName != "" {
+		return requestName
+	}
+
+	return strings.TrimSuffix(filepath.Base(templateFileName), filepath.Ext(templateFileName))
+}
+
+func exportHttp(exportParams *ExportParams) error {
+	requestFileNames, err := getExportRequestFileNames(exportParams.TemplateFileNames)
+	if err != nil {
+		return err
+	}
+
+	httpRequests := []string{}
+	untranslated := []string{}
+
+	for _, requestFileName := range requestFileNames {
+		formatExecutable := func(executable string) string {
+			formattedExecutable, translated := convert.FormatHttpExecutable(executable)
+			if !translated {
+				untranslated = append(untranslated, fmt.Sprintf("%s: executable $(%s)", requestFileName, executable))
+			}
+
+			return formattedExecutable
+		}
+
+		exportRequest, fatal, err := parse.AssembleForExport([]string{requestFileName}, convert.FormatHttpVariable, formatExecutable)
+		if err != nil {
+			return err
+		}
+
+		if fatal != "" {
+			return errors.New(fatal)
+		}
+
+		httpRequest, requestUntranslated := convert.ToHttpRequest(getExportRequestName(requestFileName), exportRequest)
+		for _, untranslatedSection := range requestUntranslated {
+			untranslated = append(untranslated, requestFileName+": "+untranslatedSection)
+		}
+
+		httpRequests = append(httpRequests, httpRequest)
+	}
+
+	if _, err := fmt.Fprint(os.Stdout, strings.Join(httpRequests, "\n")); err != nil {
+		return err
+	}
+
+	printUntranslated("exported requests", untranslated)
+
+	return nil
+}
+
+// assembleExportedRequest assembles the template the same as when
+// running ain, so what's exported is what the backend would send
+func assembleExportedRequest(requestFileName, backendOverride string) (context.Context, context.CancelFunc, *data.BackendInput, error) {
+	ctx, cancel, backendInput, fatal, err := parse.Assemble(context.Background(), []string{requestFileName}, backendOverride)
+	if err != nil {
+		return ctx, cancel, nil, err
+	}
+
+	if fatal != "" {
+		return ctx, cancel, nil, errors.New(fatal)
+	}
+
+	if unsupportedConfig := call.UnsupportedConfig(backendInput); len(unsupportedConfig) > 0 {
+		return ctx, cancel, nil, errors.New(strings.Join(unsupportedConfig, "\n"))
+	}
+
+	call.TranslateBackendOptions(backendInput)
+
+	if backendInput.OAuth2 != nil {
+		accessToken, err := oauth2.GetAccessToken(ctx, *backendInput.OAuth2, backendInput.Config)
+		if err != nil {
+			return ctx, cancel, nil, err
+		}
+
+		backendInput.Auth = &data.Auth{Scheme: data.AuthBearer, Token: accessToken}
+	}
+
+	if err := sign.SignRequest(backendInput, time.Now()); err != nil {
+		return ctx, cancel, nil, err
+	}
+
+	return ctx, cancel, backendInput, nil
+}
+
+// getHAREntry calls the request if asked to, so the entry has
+// the response too. Otherwise no backend is set up.
+func getHAREntry(requestFileName string, callRequest bool) (data.HAREntry, error) {
+	ctx, cancel, backendInput, err := assembleExportedRequest(requestFileName, "")
+	defer cancel()
+	if err != nil {
+		return data.HAREntry{}, err
+	}
+
+	if backendInput.Auth != nil && backendInput.Auth.Scheme == data.AuthDigest {
+		// The header needs the challenge from the server
+		fmt.Fprintf(os.Stderr, "%s: [Auth] digest is not in the exported request headers\n", requestFileName)
+	}
+
+	started := time.Now()
+	if !callRequest {
+		return call.GetHARRequestEntry(backendInput, started), nil
+	}
+
+	backendInput.InspectResponse = true
+
+	backendCall, err := call.Setup(backendInput)
+	if err != nil {
+		return data.HAREntry{}, err
+	}
+
+	backendOutput, callErr := backendCall.CallAsCmd(ctx)
+
+	if err := backendCall.Teardown(); err != nil {
+		return data.HAREntry{}, err
+	}
+
+	if (backendOutput == nil || backendOutput.StatusCode == 0) && callErr != nil {
+		// Exported without the response
+		fmt.Fprintf(os.Stderr, "%s: no response, %v\n", requestFileName, callErr)
+	}
+
+	return backendCall.GetHAREntry(backendOutput, started), nil
+}
+
+func exportHAR(exportParams *ExportParams, appVersion string) error {
+	requestFileNames, err := getExportRequestFileNames(exportParams.TemplateFileNames)
+	if err != nil {
+		return err
+	}
+
+	if err := disk.ReadEnvFile(exportParams.EnvFile, exportParams.EnvFile != ".env"); err != nil {
+		return err
+	}
+
+	har := data.NewHAR(appVersion)
+	for _, requestFileName := range requestFileNames {
+		harEntry, err := getHAREntry(requestFileName, exportParams.CallRequests)
+		if err != nil {
+			return err
+		}
+
+		har.Log.Entries = append(har.Log.Entries, harEntry)
+	}
+
+	// Urls and bodies are kept as is, not escaped for html
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	return errors.Wrap(encoder.Encode(har), "could not write the HAR")
+}
+
+type exportedFile struct {
+	fileName string
+	contents string
+	isConfig bool
+}
+
+// writeNewFile never overwrites, the file could be someone
+// else's or from an earlier export with other secrets
+func writeNewFile(fileName, contents string) error {
+	file, err := os.OpenFile(fileName, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		if os.IsExist(err) {
+			return errors.Errorf("%s already exists, remove it or pass another --dir", fileName)
+		}
+
+		return errors.Wrapf(err, "could not create file %s", fileName)
+	}
+
+	_, err = file.WriteString(contents)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return errors.Wrapf(err, "could not write file %s", fileName)
+}
+
+// exportConfigFiles writes a config file and any body per request
+// to the directory, to be replayed by the backend without ain
+func exportConfigFiles(exportParams *ExportParams) error {
+	if exportParams.Dir == "" {
+		return errors.Errorf("export %s writes a file per template, pass the directory to write them to with --dir", exportParams.Format)
+	}
+
+	requestFileNames, err := getExportRequestFileNames(exportParams.TemplateFileNames)
+	if err != nil {
+		return err
+	}
+
+	if err := disk.ReadEnvFile(exportParams.EnvFile, exportParams.EnvFile != ".env"); err != nil {
+		return err
+	}
+
+	backendName, configFileExt := "curl", ".curlrc"
+	if exportParams.Format == exportFormatWgetrc {
+		backendName, configFileExt = "wget", ".wgetrc"
+	}
+
+	// The config files are replayed from any directory
+	absoluteDir, err := filepath.Abs(exportParams.Dir)
+	if err != nil {
+		return errors.Wrapf(err, "could not get the absolute path of %s", exportParams.Dir)
+	}
+
+	exportedFrom := map[string]string{}
+	exportedFiles := []exportedFile{}
+	untranslated := []string{}
+
+	for _, requestFileName := range requestFileNames {
+		requestName := getExportRequestName(requestFileName)
+		if otherRequestFileName, exists := exportedFrom[requestName]; exists {
+			return errors.Errorf("both %s and %s would be exported as %s", otherRequestFileName, requestFileName, requestName+configFileExt)
+		}
+
+		exportedFrom[requestName] = requestFileName
+
+		_, cancel, backendInput, err := assembleExportedRequest(requestFileName, backendName)
+		cancel()
+		if err != nil {
+			return err
+		}
+
+		for _, leftOutBackendOption := range backendInput.LeftOutBackendOptions {
+			untranslated = append(untranslated, fmt.Sprintf("%s: [BackendOptions] %s", requestFileName, leftOutBackendOption))
+		}
+
+		if backendInput.Sign != nil {
+			untranslated = append(untranslated, fmt.Sprintf("%s: [Sign] headers are signed for the time of export", requestFileName))
+		}
+
+		configFileName := filepath.Join(exportParams.Dir, requestName+configFileExt)
+		absoluteConfigFileName := filepath.Join(absoluteDir, requestName+configFileExt)
+
+		absoluteBodyFileName := ""
+		if len(backendInput.Body) > 0 {
+			bodyFileName := filepath.Join(exportParams.Dir, requestName+".body")
+			absoluteBodyFileName = filepath.Join(absoluteDir, requestName+".body")
+			exportedFiles = append(exportedFiles, exportedFile{fileName: bodyFileName, contents: backendInput.GetBody()})
+		}
+
+		var configFile string
+		if backendName == "wget" {
+			var leftOut []string
+			configFile, leftOut = call.GetWgetrc(backendInput, absoluteConfigFileName, absoluteBodyFileName)
+			for _, leftOutOption := range leftOut {
+				untranslated = append(untranslated, fmt.Sprintf("%s: option %s", requestFileName, leftOutOption))
+			}
+		} else {
+			configFile = call.GetCurlConfig(backendInput, absoluteConfigFileName, absoluteBodyFileName)
+		}
+
+		exportedFiles = append(exportedFiles, exportedFile{fileName: configFileName, contents: configFile, isConfig: true})
+	}
+
+	// Nothing is written if any file is in the way
+	for _, exportedFile := range exportedFiles {
+		if _, err := os.Stat(exportedFile.fileName); err == nil {
+			return errors.Errorf("%s already exists, remove it or pass another --dir", exportedFile.fileName)
+		}
+	}
+
+	// The files can hold secrets from [Auth] and [OAuth2]
+	if err := os.MkdirAll(exportParams.Dir, 0700); err != nil {
+		return errors.Wrapf(err, "could not create directory %s", exportParams.Dir)
+	}
+
+	for _, exportedFile := range exportedFiles {
+		if err := writeNewFile(exportedFile.fileName, exportedFile.contents); err != nil {
+			return err
+		}
+
+		if exportedFile.isConfig {
+			fmt.Fprintln(os.Stdout, exportedFile.fileName)
+		}
+	}
+
+	printUntranslated("config files", untranslated)
+
+	return nil
+}
+
+// Export converts templates into requests for another tool
+func Export(exportParams *ExportParams, appVersion string) error {
+	if exportParams.showHelp {
+		exportParams.printUsage()
+		return nil
+	}
+
+	if exportParams.Format == "" {
+		exportParams.printUsage()
+		return errors.New("missing the format to export as")
+	}
+
+	switch exportParams.Format {
+	case exportFormatHttp:
+		return exportHttp(exportParams)
+	case exportFormatHAR:
+		return exportHAR(exportParams, appVersion)
+	case exportFormatCurlConfig, exportFormatWgetrc:
+		return exportConfigFiles(exportParams)
+	}
+
+	return errors.Errorf("unknown export format: %s, valid formats are: %s", exportParams.Format, strings.Join(exportFormats, ", "))
+}
@@ -0,0 +1,298 @@
+package ain
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jonaslu/ain/internal/pkg/convert"
+	"github.com/jonaslu/ain/internal/pkg/disk"
+	"github.com/pkg/errors"
+)
+
+const importCommandStr = "import"
+
+const (
+	importFormatCurl     = "curl"
+	importFormatOpenAPI  = "openapi"
+	importFormatPostman  = "postman"
+	importFormatInsomnia = "insomnia"
+	importFormatHttp     = "http"
+	importFormatHAR      = "har"
+)
+
+var importFormats = []string{importFormatCurl, importFormatOpenAPI, importFormatPostman, importFormatInsomnia, importFormatHttp, importFormatHAR}
+
+type ImportParams struct {
+	Format       string
+	BaseFileName string
+	Host         string
+	// Arguments after the format, what they are depends on the format
+	Args []string
+}
+
+func printImportUsage(appName string, flags []flag) {
+	w := os.Stderr
+
+	fmt.Fprintf(w, "Imports requests from other tools into ain templates.\n\n")
+	fmt.Fprintf(w, "usage: %s %s [OPTIONS] <format> [ARGUMENTS]\n", appName, importCommandStr)
+	fmt.Fprintf(w, "\nOPTIONS:\n")
+	for _, f := range flags {
+		fmt.Fprintf(w, "  %-22s %s\n", f.flagName, f.usage)
+	}
+
+	fmt.Fprintf(w, "\nFORMATS:\n")
+	fmt.Fprintf(w, "  %-22s %s\n", importFormatCurl+" [<template.ain>]", "A curl command line read from a pipe. Printed if no template file name is given")
+	fmt.Fprintf(w, "  %-22s %s\n", importFormatOpenAPI+" <spec> <dir>", "An OpenAPI 3 spec (yaml or json) written as base.ain and a template per operation in <dir>")
+	fmt.Fprintf(w, "  %-22s %s\n", importFormatPostman+" <file> <dir>", "A Postman v2.1 collection written as a template per request and the variables as a .env in <dir>")
+	fmt.Fprintf(w, "  %-22s %s\n", importFormatInsomnia+" <file> <dir>", "An Insomnia v4 export written as a template per request and the environments as .env files in <dir>")
+	fmt.Fprintf(w, "  %-22s %s\n", importFormatHttp+" <file> <dir>", "A .http file written as a template per request and the @variables as a .env in <dir>")
+	fmt.Fprintf(w, "  %-22s %s\n", importFormatHAR+" <file> <dir>", "A HAR (e g saved from browser devtools) written as a template per request in <dir>")
+}
+
+func newImportParams(appName string, args []string) *ImportParams {
+	var showHelp bool
+	baseFileName := ""
+	host := ""
+
+	flags := []flag{}
+	flags = append(flags, makeStringFlag("--base", "Move the host and headers into this base template, included by the imported template(s)", &baseFileName))
+	flags = append(flags, makeStringFlag("--host", "Only import the requests to this host (har)", &host))
+	flags = append(flags, makeBoolFlag("-h", "Show help and exit", &showHelp))
+
+	restArgs := parseFlags(appName, flags, args)
+
+	if showHelp || len(restArgs) == 0 {
+		printImportUsage(appName, flags)
+		if showHelp {
+			os.Exit(0)
+		}
+
+		os.Exit(1)
+	}
+
+	return &ImportParams{
+		Format:       restArgs[0],
+		BaseFileName: baseFileName,
+		Host:         host,
+		Args:         restArgs[1:],
+	}
+}
+
+// writeImportedTemplates writes the templates (and any base
+// template split from them) or prints a single template
+// when it's not given a file name
+func writeImportedTemplates(importParams *ImportParams, importedTemplates []convert.NamedTemplate) error {
+	if len(importedTemplates) == 1 && importedTemplates[0].FileName == "" {
+		if importParams.BaseFileName != "" {
+			return errors.New("--base needs a template file name to include the base template from")
+		}
+
+		_, err := fmt.Fprint(os.Stdout, importedTemplates[0].Template.String())
+		return err
+	}
+
+	if importParams.BaseFileName != "" {
+		templates := []*convert.Template{}
+		templateFileNames := []string{}
+
+		for _, importedTemplate := range importedTemplates {
+			templates = append(templates, importedTemplate.Template)
+			templateFileNames = append(templateFileNames, importedTemplate.FileName)
+		}
+
+		baseTemplate, err := convert.SplitBase(templates, importParams.BaseFileName, templateFileNames)
+		if err != nil {
+			return errors.Wrap(err, "could not split out the base template")
+		}
+
+		importedTemplates = append([]convert.NamedTemplate{{FileName: importParams.BaseFileName, Template: baseTemplate}}, importedTemplates...)
+	}
+
+	templateFileNames := []string{}
+	templateContents := []string{}
+
+	for _, importedTemplate := range importedTemplates {
+		templateFileNames = append(templateFileNames, importedTemplate.FileName)
+		templateContents = append(templateContents, importedTemplate.Template.String())
+	}
+
+	return disk.WriteTemplates(templateFileNames, templateContents)
+}
+
+func readImportFromPipe(format string) (string, error) {
+	stdinIsPipe, err := disk.StdinIsPipe()
+	if err != nil {
+		return "", err
+	}
+
+	if !stdinIsPipe {
+		return "", errors.Errorf("import %s reads from a pipe but ain is not connected to one", format)
+	}
+
+	input, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", errors.Wrap(err, "could not read from pipe stdin")
+	}
+
+	return string(input), nil
+}
+
+func importCurl(importParams *ImportParams) error {
+	if len(importParams.Args) > 1 {
+		return errors.New("import curl takes at most one template file name")
+	}
+
+	commandLine, err := readImportFromPipe(importFormatCurl)
+	if err != nil {
+		return err
+	}
+
+	template, err := convert.FromCurl(commandLine)
+	if err != nil {
+		return err
+	}
+
+	templateFileName := ""
+	if len(importParams.Args) == 1 {
+		templateFileName = importParams.Args[0]
+	}
+
+	return writeImportedTemplates(importParams, []convert.NamedTemplate{{FileName: templateFileName, Template: template}})
+}
+
+func importOpenAPI(importParams *ImportParams) error {
+	if len(importParams.Args) != 2 {
+		return errors.New("import openapi needs the spec file and the directory to write the templates to")
+	}
+
+	if importParams.BaseFileName != "" {
+		return errors.New("import openapi always writes the server and security to base.ain, --base cannot be used")
+	}
+
+	specFileName, templateDir := importParams.Args[0], importParams.Args[1]
+
+	specContents, err := os.ReadFile(specFileName)
+	if err != nil {
+		return errors.Wrapf(err, "could not read OpenAPI spec %s", specFileName)
+	}
+
+	namedTemplates, err := convert.FromOpenAPI(specContents)
+	if err != nil {
+		return err
+	}
+
+	for i := range namedTemplates {
+		namedTemplates[i].FileName = filepath.Join(templateDir, filepath.FromSlash(namedTemplates[i].FileName))
+	}
+
+	return writeImportedTemplates(importParams, namedTemplates)
+}
+
+func importHAR(importParams *ImportParams) error {
+	if len(importParams.Args) != 2 {
+		return errors.New("import har needs the HAR file and the directory to write the templates to")
+	}
+
+	harFileName, templateDir := importParams.Args[0], importParams.Args[1]
+
+	harContents, err := os.ReadFile(harFileName)
+	if err != nil {
+		return errors.Wrapf(err, "could not read HAR %s", harFileName)
+	}
+
+	namedTemplates, err := convert.FromHAR(harContents, importParams.Host)
+	if err != nil {
+		return err
+	}
+
+	for i := range namedTemplates {
+		namedTemplates[i].FileName = filepath.Join(templateDir, namedTemplates[i].FileName)
+	}
+
+	return writeImportedTemplates(importParams, namedTemplates)
+}
+
+// printUntranslated lists what was imported or exported
+// but could not be translated into the other format
+func printUntranslated(translatedInto string, untranslated []string) {
+	if len(untranslated) == 0 {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "Could not translate (the %s need editing by hand):\n", translatedInto)
+	for _, untranslatedLine := range untranslated {
+		fmt.Fprintf(os.Stderr, "  %s\n", untranslatedLine)
+	}
+}
+
+// importCollection writes the templates and .env files from an API
+// client into the directory and reports what could not be translated
+func importCollection(importParams *ImportParams, fromCollection func([]byte) (*convert.Collection, error)) error {
+	if len(importParams.Args) != 2 {
+		return errors.Errorf("import %s needs the exported file and the directory to write the templates to", importParams.Format)
+	}
+
+	if importParams.BaseFileName != "" {
+		return errors.Errorf("import %s always includes base.ain in the templates, --base cannot be used", importParams.Format)
+	}
+
+	exportFileName, templateDir := importParams.Args[0], importParams.Args[1]
+
+	exportContents, err := os.ReadFile(exportFileName)
+	if err != nil {
+		return errors.Wrapf(err, "could not read %s export %s", importParams.Format, exportFileName)
+	}
+
+	collection, err := fromCollection(exportContents)
+	if err != nil {
+		return err
+	}
+
+	fileNames := []string{}
+	contents := []string{}
+
+	for _, namedTemplate := range collection.Templates {
+		fileNames = append(fileNames, filepath.Join(templateDir, filepath.FromSlash(namedTemplate.FileName)))
+		contents = append(contents, namedTemplate.Template.String())
+	}
+
+	for _, envFile := range collection.EnvFiles {
+		fileNames = append(fileNames, filepath.Join(templateDir, envFile.FileName))
+		contents = append(contents, envFile.String())
+	}
+
+	if err := disk.WriteTemplates(fileNames, contents); err != nil {
+		return err
+	}
+
+	for _, envFile := range collection.EnvFiles {
+		fmt.Fprintf(os.Stderr, "Variables written to %s, pass it to ain with -e\n", filepath.Join(templateDir, envFile.FileName))
+	}
+
+	printUntranslated("templates", collection.Untranslated)
+
+	return nil
+}
+
+// Import converts requests from another tool into templates
+func Import(importParams *ImportParams) error {
+	switch importParams.Format {
+	case importFormatCurl:
+		return importCurl(importParams)
+	case importFormatOpenAPI:
+		return importOpenAPI(importParams)
+	case importFormatPostman:
+		return importCollection(importParams, convert.FromPostman)
+	case importFormatInsomnia:
+		return importCollection(importParams, convert.FromInsomnia)
+	case importFormatHttp:
+		return importCollection(importParams, convert.FromHttpFile)
+	case importFormatHAR:
+		return importHAR(importParams)
+	}
+
+	return errors.Errorf("unknown import format: %s, valid formats are: %s", importParams.Format, strings.Join(importFormats, ", "))
+}
@@ -0,0 +1,35 @@
+package call
+
+import (
+	"github.com/jonaslu/ain/internal/pkg/data"
+)
+
+// getAuth returns any [Auth] with the secret masked
+// when the command is printed rather than run
+func getAuth(backendInput *data.BackendInput, mask bool) *data.Auth {
+	if backendInput.Auth == nil {
+		return nil
+	}
+
+	auth := *backendInput.Auth
+	if mask {
+		auth = auth.Masked()
+	}
+
+	return &auth
+}
+
+// getHeaders returns the [Headers] with any session token
+// from [Sign] masked when the command is printed rather than run
+func getHeaders(backendInput *data.BackendInput, mask bool) []string {
+	if !mask {
+		return backendInput.Headers
+	}
+
+	headers := []string{}
+	for _, header := range backendInput.Headers {
+		headers = append(headers, data.MaskSessionTokenHeader(header))
+	}
+
+	return headers
+}
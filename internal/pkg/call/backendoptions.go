@@ -0,0 +1,162 @@
+package call
+
+import (
+	"strings"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+)
+
+// translatedOption is the same option for each backend
+type translatedOption struct {
+	// The names of the option for each backend, the first is used in
+	// translations. An empty list is when the backend does it without
+	// an option and a missing backend is when it has no such option.
+	names      map[string][]string
+	takesValue bool
+}
+
+var translatedOptions = []translatedOption{
+	{names: map[string][]string{"curl": {"-k", "--insecure"}, "httpie": {"--verify=no", "--verify=false"}, "wget": {"--no-check-certificate"}}},
+	{names: map[string][]string{"curl": {"-L", "--location"}, "httpie": {"-F", "--follow"}, "wget": {}}},
+	{names: map[string][]string{"curl": {"-s", "--silent"}, "httpie": {}, "wget": {"-q", "--quiet"}}},
+	{names: map[string][]string{"curl": {"-S", "--show-error"}, "httpie": {}, "wget": {}}},
+	{names: map[string][]string{"curl": {"--compressed"}, "httpie": {}}},
+	{names: map[string][]string{"curl": {}, "httpie": {"--ignore-stdin"}, "wget": {}}},
+	{names: map[string][]string{"curl": {}, "httpie": {}, "wget": {"-O-", "--output-document=-"}}},
+	{names: map[string][]string{"curl": {"--max-redirs"}, "httpie": {"--max-redirects"}, "wget": {"--max-redirect"}}, takesValue: true},
+	// Curl's --max-time limits the whole call, the others limit each wait
+	{names: map[string][]string{"httpie": {"--timeout"}, "wget": {"-T", "--timeout"}}, takesValue: true},
+	{names: map[string][]string{"curl": {"--connect-timeout"}, "wget": {"--connect-timeout"}}, takesValue: true},
+	{names: map[string][]string{"curl": {"-A", "--user-agent"}, "wget": {"-U", "--user-agent"}}, takesValue: true},
+	{names: map[string][]string{"curl": {"--cacert"}, "wget": {"--ca-certificate"}}, takesValue: true},
+	{names: map[string][]string{"curl": {"-E", "--cert"}, "httpie": {"--cert"}, "wget": {"--certificate"}}, takesValue: true},
+	{names: map[string][]string{"curl": {"--key"}, "httpie": {"--cert-key"}, "wget": {"--private-key"}}, takesValue: true},
+}
+
+// findTranslatedOption returns the option the argument is for the
+// backend and any value given in the same argument (-m10, --timeout=10)
+func findTranslatedOption(backendName, arg string) (*translatedOption, string, bool) {
+	for i, option := range translatedOptions {
+		for _, name := range option.names[backendName] {
+			if arg == name {
+				return &translatedOptions[i], "", false
+			}
+
+			if !option.takesValue {
+				continue
+			}
+
+			if strings.HasPrefix(name, "--") && strings.HasPrefix(arg, name+"=") {
+				return &translatedOptions[i], strings.TrimPrefix(arg, name+"="), true
+			}
+
+			if !strings.HasPrefix(name, "--") && len(arg) > len(name) && strings.HasPrefix(arg, name) {
+				return &translatedOptions[i], strings.TrimPrefix(arg, name), true
+			}
+		}
+	}
+
+	return nil, "", false
+}
+
+// splitShortOptions splits -sS into -s and -S when all
+// are options without a value known for the backend
+func splitShortOptions(backendName string, backendOptionLine []string) []string {
+	splitLine := []string{}
+
+	for _, arg := range backendOptionLine {
+		if option, _, _ := findTranslatedOption(backendName, arg); option != nil || len(arg) < 3 || arg[0] != '-' || arg[1] == '-' {
+			splitLine = append(splitLine, arg)
+			continue
+		}
+
+		shortOptions := []string{}
+		for _, shortOption := range arg[1:] {
+			option, _, _ := findTranslatedOption(backendName, "-"+string(shortOption))
+			if option == nil || option.takesValue {
+				shortOptions = []string{arg}
+				break
+			}
+
+			shortOptions = append(shortOptions, "-"+string(shortOption))
+		}
+
+		splitLine = append(splitLine, shortOptions...)
+	}
+
+	return splitLine
+}
+
+// TranslateBackendOptions replaces the [BackendOptions] written for the
+// [Backend] in the templates with the options for the backend given
+// with ain --as, options without a counterpart there are left out
+func TranslateBackendOptions(backendInput *data.BackendInput) {
+	backendInput.BackendOptions, backendInput.LeftOutBackendOptions = translateBackendOptions(backendInput.TemplateBackend, backendInput.Backend, backendInput.BackendOptions)
+}
+
+// translateBackendOptions returns the [BackendOptions] written for one
+// backend as the options for another and the options that have no
+// counterpart there and are left out
+func translateBackendOptions(fromBackend, toBackend string, backendOptions [][]string) ([][]string, []string) {
+	if fromBackend == "" || fromBackend == toBackend {
+		return backendOptions, nil
+	}
+
+	translatedBackendOptions := [][]string{}
+	leftOut := []string{}
+
+	for _, backendOptionLine := range backendOptions {
+		backendOptionLine = splitShortOptions(fromBackend, backendOptionLine)
+		translatedLine := []string{}
+
+		for i := 0; i < len(backendOptionLine); i++ {
+			arg := backendOptionLine[i]
+
+			option, value, hasValue := findTranslatedOption(fromBackend, arg)
+			if option == nil {
+				// Unknown, so any values after it are left out with it
+				leftOutArgs := []string{arg}
+				for i+1 < len(backendOptionLine) && !strings.HasPrefix(backendOptionLine[i+1], "-") {
+					i++
+					leftOutArgs = append(leftOutArgs, backendOptionLine[i])
+				}
+
+				leftOut = append(leftOut, strings.Join(leftOutArgs, " "))
+				continue
+			}
+
+			givenArgs := arg
+			if option.takesValue && !hasValue {
+				if i+1 == len(backendOptionLine) {
+					leftOut = append(leftOut, arg+" (missing value)")
+					continue
+				}
+
+				i++
+				value = backendOptionLine[i]
+				givenArgs = arg + " " + value
+			}
+
+			names, exists := option.names[toBackend]
+			if !exists {
+				leftOut = append(leftOut, givenArgs)
+				continue
+			}
+
+			if len(names) == 0 {
+				continue
+			}
+
+			translatedLine = append(translatedLine, names[0])
+			if option.takesValue {
+				translatedLine = append(translatedLine, value)
+			}
+		}
+
+		if len(translatedLine) > 0 {
+			translatedBackendOptions = append(translatedBackendOptions, translatedLine)
+		}
+	}
+
+	return translatedBackendOptions, leftOut
+}
@@ -0,0 +1,89 @@
+package call
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_translateBackendOptions(t *testing.T) {
+	tests := map[string]struct {
+		fromBackend        string
+		toBackend          string
+		backendOptions     [][]string
+		expectedTranslated [][]string
+		expectedLeftOut    []string
+	}{
+		"Same backend kept as is": {
+			fromBackend:        "curl",
+			toBackend:          "curl",
+			backendOptions:     [][]string{{"-x", "http://proxy"}},
+			expectedTranslated: [][]string{{"-x", "http://proxy"}},
+		},
+		"No [Backend] kept as is": {
+			toBackend:          "wget",
+			backendOptions:     [][]string{{"-x", "http://proxy"}},
+			expectedTranslated: [][]string{{"-x", "http://proxy"}},
+		},
+		"Values separate, after = and attached": {
+			fromBackend:        "curl",
+			toBackend:          "wget",
+			backendOptions:     [][]string{{"--connect-timeout", "10"}, {"--user-agent=ain"}, {"-Aain"}},
+			expectedTranslated: [][]string{{"--connect-timeout", "10"}, {"-U", "ain"}, {"-U", "ain"}},
+			expectedLeftOut:    []string{},
+		},
+		"Max time for the whole call left out": {
+			fromBackend:        "curl",
+			toBackend:          "wget",
+			backendOptions:     [][]string{{"-m", "10"}, {"--max-time=5"}},
+			expectedTranslated: [][]string{},
+			expectedLeftOut:    []string{"-m 10", "--max-time=5"},
+		},
+		"Timeout for each wait": {
+			fromBackend:        "httpie",
+			toBackend:          "wget",
+			backendOptions:     [][]string{{"--timeout=10"}},
+			expectedTranslated: [][]string{{"-T", "10"}},
+			expectedLeftOut:    []string{},
+		},
+		"Short options split": {
+			fromBackend:        "curl",
+			toBackend:          "wget",
+			backendOptions:     [][]string{{"-kL"}},
+			expectedTranslated: [][]string{{"--no-check-certificate"}},
+			expectedLeftOut:    []string{},
+		},
+		"Default in the other backend dropped": {
+			fromBackend:        "httpie",
+			toBackend:          "curl",
+			backendOptions:     [][]string{{"--ignore-stdin"}, {"--follow"}},
+			expectedTranslated: [][]string{{"-L"}},
+			expectedLeftOut:    []string{},
+		},
+		"Unknown left out with its values": {
+			fromBackend:        "curl",
+			toBackend:          "httpie",
+			backendOptions:     [][]string{{"-x", "http://proxy", "-k"}, {"--cacert", "ca.pem"}, {"-sX"}},
+			expectedTranslated: [][]string{{"--verify=no"}},
+			expectedLeftOut:    []string{"-x http://proxy", "--cacert ca.pem", "-sX"},
+		},
+		"Missing value left out": {
+			fromBackend:        "wget",
+			toBackend:          "curl",
+			backendOptions:     [][]string{{"--timeout"}},
+			expectedTranslated: [][]string{},
+			expectedLeftOut:    []string{"--timeout (missing value)"},
+		},
+	}
+
+	for name, test := range tests {
+		translated, leftOut := translateBackendOptions(test.fromBackend, test.toBackend, test.backendOptions)
+
+		if !reflect.DeepEqual(translated, test.expectedTranslated) {
+			t.Errorf("Test: %s, Expected: %v, Got: %v", name, test.expectedTranslated, translated)
+		}
+
+		if !reflect.DeepEqual(leftOut, test.expectedLeftOut) {
+			t.Errorf("Test: %s, Expected left out: %v, Got: %v", name, test.expectedLeftOut, leftOut)
+		}
+	}
+}
@@ -3,7 +3,11 @@ package call
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"os"
 	"os/exec"
+	"sort"
+	"time"
 
 	"github.com/jonaslu/ain/internal/pkg/data"
 	"github.com/pkg/errors"
@@ -12,38 +16,97 @@ import (
 type backendConstructor struct {
 	BinaryName  string
 	constructor func(*data.BackendInput, string) backend
+	// Can read the body from stdin without it being written to a file first
+	streamsStdinBody bool
+	// Returns why any [Config] cannot be translated for the backend
+	unsupportedConfig func(data.Config) []string
 }
 
 var ValidBackends = map[string]backendConstructor{
 	"curl": {
-		BinaryName:  "curl",
-		constructor: newCurlBackend,
+		BinaryName:       "curl",
+		constructor:      newCurlBackend,
+		streamsStdinBody: true,
 	},
 	"httpie": {
-		BinaryName:  "http",
-		constructor: newHttpieBackend,
+		BinaryName:        "http",
+		constructor:       newHttpieBackend,
+		streamsStdinBody:  true,
+		unsupportedConfig: httpieUnsupportedConfig,
 	},
 	"wget": {
-		BinaryName:  "wget",
-		constructor: newWgetBackend,
+		BinaryName:        "wget",
+		constructor:       newWgetBackend,
+		unsupportedConfig: wgetUnsupportedConfig,
 	},
 }
 
 type backend interface {
+	printer
 	getAsCmd(context.Context) *exec.Cmd
-	getAsString() string
+	// The [BackendOptions] already print the response head to stdout
+	printsResponseHead() bool
+}
+
+func hasBackendOption(backendOptions [][]string, isOption func(string) bool) bool {
+	for _, backendOptionLine := range backendOptions {
+		for _, backendOption := range backendOptionLine {
+			if isOption(backendOption) {
+				return true
+			}
+		}
+	}
+
+	return false
 }
 
 func getBackend(backendInput *data.BackendInput) (backend, error) {
 	requestedBackend := backendInput.Backend
 
 	if backendConstructor, exists := ValidBackends[requestedBackend]; exists {
+		// A printed command is shared or run later and a retried
+		// call sends the body again, so the body is kept in a
+		// file instead of the pipe
+		if backendInput.BodyFromStdin && (backendInput.PrintCommand || !backendConstructor.streamsStdinBody || getRetries(backendInput.Config) > 0) {
+			if err := backendInput.ReadBodyFromStdin(); err != nil {
+				return nil, err
+			}
+		} else if backendInput.BodyFromStdin {
+			if err := backendInput.PeekBodyFromStdin(); err != nil {
+				return nil, err
+			}
+		}
+
 		return backendConstructor.constructor(backendInput, backendConstructor.BinaryName), nil
 	}
 
 	return nil, errors.Errorf("Unknown backend: %s", requestedBackend)
 }
 
+// UnsupportedConfig returns a fatal for each [Config] the backend
+// cannot translate into its own options, checked after assembly
+// the same as the template fatals
+func UnsupportedConfig(backendInput *data.BackendInput) []string {
+	backendConstructor, exists := ValidBackends[backendInput.Backend]
+	if !exists || backendConstructor.unsupportedConfig == nil {
+		return nil
+	}
+
+	return backendConstructor.unsupportedConfig(backendInput.Config)
+}
+
+// BackendNames returns the backends sorted by name
+func BackendNames() []string {
+	backendNames := []string{}
+	for backendName := range ValidBackends {
+		backendNames = append(backendNames, backendName)
+	}
+
+	sort.Strings(backendNames)
+
+	return backendNames
+}
+
 func ValidBackend(backendName string) bool {
 	if _, exists := ValidBackends[backendName]; exists {
 		return true
@@ -68,6 +131,14 @@ func Setup(backendInput *data.BackendInput) (*Call, error) {
 
 	call.backend = backend
 
+	if err := checkInlineBodyShell(backendInput); err != nil {
+		return nil, err
+	}
+
+	if getRetries(backendInput.Config) > 0 {
+		backendInput.InspectResponse = true
+	}
+
 	if err := backendInput.CreateBodyTempFile(); err != nil {
 		return nil, err
 	}
@@ -79,14 +150,20 @@ func (c *Call) CallAsString() string {
 	return c.backend.getAsString()
 }
 
-func (c *Call) CallAsCmd(ctx context.Context) (*data.BackendOutput, error) {
+func (c *Call) callAsCmdOnce(ctx context.Context) (*data.BackendOutput, error) {
 	backendCmd := c.backend.getAsCmd(ctx)
 
+	if c.backendInput.BodyFromStdin {
+		backendCmd.Stdin = c.backendInput.StdinBody
+	}
+
 	var stdout, stderr bytes.Buffer
 	backendCmd.Stdout = &stdout
 	backendCmd.Stderr = &stderr
 
+	callStart := time.Now()
 	err := backendCmd.Run()
+	callDuration := time.Since(callStart)
 
 	c.forceRemoveTempFile = err != nil
 
@@ -94,14 +171,15 @@ func (c *Call) CallAsCmd(ctx context.Context) (*data.BackendOutput, error) {
 		Stderr:   stderr.String(),
 		Stdout:   stdout.String(),
 		ExitCode: backendCmd.ProcessState.ExitCode(),
+		Duration: callDuration,
 	}
 
-	if ctx.Err() == context.DeadlineExceeded {
-		err = errors.Errorf("Backend-call: %s timed out after %d seconds",
-			c.backendInput.Backend,
-			ctx.Value(data.TimeoutContextValueKey{}))
+	if c.backendInput.InspectResponse {
+		inspectResponse(backendOutput, c.backend.printsResponseHead())
+	}
 
-		return backendOutput, err
+	if ctx.Err() == context.DeadlineExceeded {
+		return backendOutput, c.timedOutError(ctx)
 	}
 
 	if err != nil {
@@ -111,6 +189,43 @@ func (c *Call) CallAsCmd(ctx context.Context) (*data.BackendOutput, error) {
 	return backendOutput, nil
 }
 
+func (c *Call) timedOutError(ctx context.Context) error {
+	return errors.Errorf("Backend-call: %s timed out after %d seconds",
+		c.backendInput.Backend,
+		ctx.Value(data.TimeoutContextValueKey{}))
+}
+
+func (c *Call) CallAsCmd(ctx context.Context) (*data.BackendOutput, error) {
+	retries := getRetries(c.backendInput.Config)
+
+	for attempt := 0; ; attempt++ {
+		backendOutput, err := c.callAsCmdOnce(ctx)
+		if attempt == retries || ctx.Err() != nil {
+			return backendOutput, err
+		}
+
+		retryReason, retry := getRetryReason(c.backendInput.Config, backendOutput)
+		if !retry {
+			return backendOutput, err
+		}
+
+		retryDelay := getRetryDelay(c.backendInput.Config, attempt, backendOutput.Headers, time.Now())
+		fmt.Fprintf(os.Stderr, "Retrying (%d/%d) in %s: %s\n", attempt+1, retries, retryDelay, retryReason)
+
+		retryTimer := time.NewTimer(retryDelay)
+		select {
+		case <-ctx.Done():
+			retryTimer.Stop()
+			if ctx.Err() == context.DeadlineExceeded {
+				return backendOutput, c.timedOutError(ctx)
+			}
+
+			return backendOutput, ctx.Err()
+		case <-retryTimer.C:
+		}
+	}
+}
+
 func (c *Call) Teardown() error {
 	return c.backendInput.RemoveBodyTempFile(c.forceRemoveTempFile)
 }
@@ -0,0 +1,219 @@
+package call
+
+import (
+	"strings"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+	"github.com/jonaslu/ain/internal/pkg/utils"
+)
+
+// The options ain passes to curl by their short name,
+// written by the long name in the config file
+var curlLongOptionNames = map[string]string{
+	"-X": "request",
+	"-H": "header",
+	"-u": "user",
+	"-b": "cookie",
+	"-c": "cookie-jar",
+}
+
+var curlConfigQuotes = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\t", `\t`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\v", `\v`,
+)
+
+// Curl reads \\, \", \t, \n, \r and \v in a double quoted value
+func quoteCurlConfig(value string) string {
+	return `"` + curlConfigQuotes.Replace(value) + `"`
+}
+
+// isOptionValue is true when the argument after an option is its value
+// rather than the next option. A lone dash is stdin or stdout.
+func isOptionValue(arg string) bool {
+	return arg == "-" || !strings.HasPrefix(arg, "-")
+}
+
+// getCurlConfigLines writes the options one per line, a long option
+// as name = "value" and a short option as -x "value"
+func getCurlConfigLines(args []string) []string {
+	lines := []string{}
+
+	for i := 0; i < len(args); i++ {
+		option := args[i]
+		if longOptionName, exists := curlLongOptionNames[option]; exists {
+			option = "--" + longOptionName
+		}
+
+		separator := " "
+		if strings.HasPrefix(option, "--") {
+			option = strings.TrimPrefix(option, "--")
+			separator = " = "
+		}
+
+		if i+1 < len(args) && isOptionValue(args[i+1]) {
+			i++
+			lines = append(lines, option+separator+quoteCurlConfig(args[i]))
+			continue
+		}
+
+		lines = append(lines, option)
+	}
+
+	return lines
+}
+
+// GetCurlConfig returns the request as a curl config file to be run
+// with curl -K, with any body read from bodyFileName. The file names should
+// be absolute, curl reads them relative to where it's run. The
+// [BackendOptions] must already be for curl.
+func GetCurlConfig(backendInput *data.BackendInput, configFileName, bodyFileName string) string {
+	backendInput.TempFileName = bodyFileName
+	curl := newCurlBackend(backendInput, "curl").(*curl)
+
+	args := []string{}
+	for _, backendOptionLine := range backendInput.BackendOptions {
+		args = append(args, backendOptionLine...)
+	}
+
+	args = append(args, curl.getMethodArgument(false)...)
+	for _, headerArgs := range curl.getHeaderArguments(false) {
+		args = append(args, headerArgs...)
+	}
+
+	args = append(args, curl.getAuthArguments(false)...)
+	args = append(args, curl.getCookieJarArguments(false)...)
+	args = append(args, curl.getConfigArguments(false)...)
+	args = append(args, curl.getBodyArgument()...)
+	args = append(args, "--url", backendInput.Host.String())
+
+	posixShell := utils.GetShell(utils.DefaultShell)
+
+	var builder strings.Builder
+	builder.WriteString("# curl -K " + posixShell.Escape(configFileName) + "\n")
+	for _, line := range getCurlConfigLines(args) {
+		builder.WriteString(line + "\n")
+	}
+
+	return builder.String()
+}
+
+// The short options wget has a wgetrc command for
+var wgetShortOptionCommands = map[string]struct {
+	command    string
+	takesValue bool
+}{
+	"-O": {command: "output_document", takesValue: true},
+	"-T": {command: "timeout", takesValue: true},
+	"-U": {command: "user_agent", takesValue: true},
+	"-t": {command: "tries", takesValue: true},
+	"-q": {command: "quiet"},
+	"-v": {command: "verbose"},
+	"-d": {command: "debug"},
+	"-S": {command: "server_response"},
+	"-4": {command: "inet4_only"},
+	"-6": {command: "inet6_only"},
+}
+
+// getWgetrcLines turns the options into wgetrc commands: --name=value
+// is name = value, --name is name = on and --no-name is name = off.
+// A -e command is written as is. Options with no command are left out.
+func getWgetrcLines(args []string) ([]string, []string) {
+	lines := []string{}
+	leftOut := []string{}
+
+	for i := 0; i < len(args); i++ {
+		option := args[i]
+		hasNextValue := i+1 < len(args) && isOptionValue(args[i+1])
+
+		if option == "-e" || option == "--execute" {
+			if hasNextValue {
+				i++
+				lines = append(lines, args[i])
+			}
+
+			continue
+		}
+
+		if strings.HasPrefix(option, "--") {
+			name, value, hasValue := strings.Cut(strings.TrimPrefix(option, "--"), "=")
+			if !hasValue && hasNextValue {
+				i++
+				value, hasValue = args[i], true
+			}
+
+			if !hasValue {
+				value = "on"
+				if strings.HasPrefix(name, "no-") {
+					name, value = strings.TrimPrefix(name, "no-"), "off"
+				}
+			}
+
+			lines = append(lines, strings.ReplaceAll(name, "-", "_")+" = "+value)
+			continue
+		}
+
+		if len(option) < 2 {
+			leftOut = append(leftOut, option)
+			continue
+		}
+
+		shortOption, exists := wgetShortOptionCommands[option[:2]]
+		if !exists {
+			leftOut = append(leftOut, option)
+			continue
+		}
+
+		switch {
+		case shortOption.takesValue && len(option) > 2:
+			lines = append(lines, shortOption.command+" = "+option[2:])
+		case shortOption.takesValue && hasNextValue:
+			i++
+			lines = append(lines, shortOption.command+" = "+args[i])
+		case !shortOption.takesValue && len(option) == 2:
+			lines = append(lines, shortOption.command+" = on")
+		default:
+			leftOut = append(leftOut, option)
+		}
+	}
+
+	return lines, leftOut
+}
+
+// GetWgetrc returns the request as a wgetrc file to be run with
+// wget --config, with any body read from bodyFileName. The url is
+// not a wgetrc command so it's in the command at the top. The
+// [BackendOptions] must already be for wget.
+func GetWgetrc(backendInput *data.BackendInput, configFileName, bodyFileName string) (string, []string) {
+	backendInput.TempFileName = bodyFileName
+	wget := newWgetBackend(backendInput, "wget").(*wget)
+
+	args := []string{}
+	for _, backendOptionLine := range backendInput.BackendOptions {
+		args = append(args, backendOptionLine...)
+	}
+
+	if backendInput.Method != "" {
+		args = append(args, wget.getMethodArgument(false))
+	}
+
+	args = append(args, wget.getHeaderArguments(false)...)
+	args = append(args, wget.getAuthArguments(false)...)
+	args = append(args, wget.getCookieJarArguments(false)...)
+	args = append(args, wget.getConfigArguments(false)...)
+	args = append(args, wget.getBodyArgument()...)
+
+	posixShell := utils.GetShell(utils.DefaultShell)
+	lines, leftOut := getWgetrcLines(args)
+
+	var builder strings.Builder
+	builder.WriteString("# wget --config=" + posixShell.Escape(configFileName) + " " + posixShell.Escape(backendInput.Host.String()) + "\n")
+	for _, line := range lines {
+		builder.WriteString(line + "\n")
+	}
+
+	return builder.String(), leftOut
+}
@@ -0,0 +1,102 @@
+package call
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+)
+
+func Test_getCurlConfigLines(t *testing.T) {
+	tests := map[string]struct {
+		args     []string
+		expected []string
+	}{
+		"Long names for ain's short options": {
+			args:     []string{"-X", "POST", "-H", "Accept: */*", "-b", "jar", "-c", "jar"},
+			expected: []string{`request = "POST"`, `header = "Accept: */*"`, `cookie = "jar"`, `cookie-jar = "jar"`},
+		},
+		"Options without values": {
+			args:     []string{"-sS", "--insecure", "--max-time", "10"},
+			expected: []string{"-sS", "insecure", `max-time = "10"`},
+		},
+		"Short option value and lone dash": {
+			args:     []string{"-x", "http://proxy", "-o", "-"},
+			expected: []string{`-x "http://proxy"`, `-o "-"`},
+		},
+		"Hostile value": {
+			args:     []string{"-H", "X-Q: \"hi\" \\o/\t$HOME\n"},
+			expected: []string{`header = "X-Q: \"hi\" \\o/\t$HOME\n"`},
+		},
+	}
+
+	for name, test := range tests {
+		if got := getCurlConfigLines(test.args); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("Test: %s, Expected: %v, Got: %v", name, test.expected, got)
+		}
+	}
+}
+
+func Test_getWgetrcLines(t *testing.T) {
+	tests := map[string]struct {
+		args            []string
+		expectedLines   []string
+		expectedLeftOut []string
+	}{
+		"Long options": {
+			args:            []string{"--header=X-A: a=b", "--no-check-certificate", "--keep-session-cookies", "--max-redirect", "0"},
+			expectedLines:   []string{"header = X-A: a=b", "check_certificate = off", "keep_session_cookies = on", "max_redirect = 0"},
+			expectedLeftOut: []string{},
+		},
+		"Short options": {
+			args:            []string{"-O-", "-q", "-T", "10"},
+			expectedLines:   []string{"output_document = -", "quiet = on", "timeout = 10"},
+			expectedLeftOut: []string{},
+		},
+		"Execute as is": {
+			args:            []string{"-e", "use_proxy=on", "-e", "http_proxy=http://proxy"},
+			expectedLines:   []string{"use_proxy=on", "http_proxy=http://proxy"},
+			expectedLeftOut: []string{},
+		},
+		"Unknown short options left out": {
+			args:            []string{"-qS", "-nv"},
+			expectedLines:   []string{},
+			expectedLeftOut: []string{"-qS", "-nv"},
+		},
+	}
+
+	for name, test := range tests {
+		lines, leftOut := getWgetrcLines(test.args)
+
+		if !reflect.DeepEqual(lines, test.expectedLines) {
+			t.Errorf("Test: %s, Expected: %v, Got: %v", name, test.expectedLines, lines)
+		}
+
+		if !reflect.DeepEqual(leftOut, test.expectedLeftOut) {
+			t.Errorf("Test: %s, Expected left out: %v, Got: %v", name, test.expectedLeftOut, leftOut)
+		}
+	}
+}
+
+func Test_GetCurlConfig(t *testing.T) {
+	hostUrl, _ := url.Parse("http://localhost:8080/users")
+
+	got := GetCurlConfig(&data.BackendInput{
+		Host: hostUrl,
+		Body: []string{"{}"},
+		Auth: &data.Auth{Scheme: data.AuthBasic, Username: "user", Password: "s3cr3t"},
+	}, "out/create-user.curlrc", "out/create-user.body")
+
+	for _, expected := range []string{
+		"# curl -K 'out/create-user.curlrc'\n",
+		"user = \"user:s3cr3t\"\n",
+		"data-binary = \"@out/create-user.body\"\n",
+		"url = \"http://localhost:8080/users\"\n",
+	} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("Test: curl config, Expected: %s, Got: %s", expected, got)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package call
 import (
 	"context"
 	"os/exec"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/jonaslu/ain/internal/pkg/data"
@@ -12,21 +14,23 @@ import (
 type curl struct {
 	backendInput *data.BackendInput
 	binaryName   string
+	shell        utils.Shell
 }
 
 func newCurlBackend(backendInput *data.BackendInput, binaryName string) backend {
 	return &curl{
 		backendInput: backendInput,
 		binaryName:   binaryName,
+		shell:        utils.GetShell(backendInput.PrintShell),
 	}
 }
 
 func (curl *curl) getHeaderArguments(escape bool) [][]string {
 	args := [][]string{}
-	for _, header := range curl.backendInput.Headers {
+	for _, header := range getHeaders(curl.backendInput, escape) {
 		headerVal := header
 		if escape {
-			headerVal = utils.EscapeForShell(header)
+			headerVal = curl.shell.Escape(header)
 		}
 
 		args = append(args, []string{"-H", headerVal})
@@ -39,7 +43,7 @@ func (curl *curl) getMethodArgument(escape bool) []string {
 	if curl.backendInput.Method != "" {
 		methodCapitalized := strings.ToUpper(curl.backendInput.Method)
 		if escape {
-			methodCapitalized = utils.EscapeForShell(methodCapitalized)
+			methodCapitalized = curl.shell.Escape(methodCapitalized)
 		}
 
 		return []string{"-X", methodCapitalized}
@@ -48,14 +52,163 @@ func (curl *curl) getMethodArgument(escape bool) []string {
 	return []string{}
 }
 
+func (curl *curl) getAuthArguments(escape bool) []string {
+	auth := getAuth(curl.backendInput, escape)
+	if auth == nil {
+		return []string{}
+	}
+
+	usernameAndPassword := auth.UsernameAndPassword()
+	bearerHeader := auth.BearerHeader()
+	if escape {
+		usernameAndPassword = curl.shell.Escape(usernameAndPassword)
+		bearerHeader = curl.shell.Escape(bearerHeader)
+	}
+
+	switch auth.Scheme {
+	case data.AuthBasic:
+		return []string{"-u", usernameAndPassword}
+	case data.AuthDigest:
+		return []string{"--digest", "-u", usernameAndPassword}
+	case data.AuthBearer:
+		return []string{"-H", bearerHeader}
+	}
+
+	return []string{}
+}
+
+func (curl *curl) getCookieJarArguments(escape bool) []string {
+	cookieJar := curl.backendInput.Config.CookieJar
+	if cookieJar == nil {
+		return []string{}
+	}
+
+	cookieJarFilename := *cookieJar
+	if escape {
+		cookieJarFilename = curl.shell.Escape(cookieJarFilename)
+	}
+
+	// Reads the cookies before the call and writes
+	// the cookies (including any new) after
+	return []string{"-b", cookieJarFilename, "-c", cookieJarFilename}
+}
+
+func (curl *curl) getConfigArguments(escape bool) []string {
+	config := curl.backendInput.Config
+	args := []string{}
+
+	if config.Proxy != nil {
+		proxy := *config.Proxy
+		if escape {
+			proxy = curl.shell.Escape(proxy)
+		}
+
+		args = append(args, "--proxy", proxy)
+	}
+
+	if config.CACert != nil {
+		caCert := *config.CACert
+		if escape {
+			caCert = curl.shell.Escape(caCert)
+		}
+
+		args = append(args, "--cacert", caCert)
+	}
+
+	if config.ClientCert != nil {
+		clientCert := *config.ClientCert
+		if escape {
+			clientCert = curl.shell.Escape(clientCert)
+		}
+
+		args = append(args, "--cert", clientCert)
+	}
+
+	if config.ClientKey != nil {
+		clientKey := *config.ClientKey
+		if escape {
+			clientKey = curl.shell.Escape(clientKey)
+		}
+
+		args = append(args, "--key", clientKey)
+	}
+
+	if data.IsTrue(config.Insecure) {
+		args = append(args, "--insecure")
+	}
+
+	if config.FollowsRedirects() {
+		args = append(args, "--location")
+	}
+
+	if config.MaxRedirects != nil {
+		args = append(args, "--max-redirs", strconv.Itoa(int(*config.MaxRedirects)))
+	}
+
+	if data.IsTrue(config.Verbose) {
+		args = append(args, "--verbose")
+	}
+
+	if data.IsTrue(config.Compressed) {
+		args = append(args, "--compressed")
+	}
+
+	if config.HTTPVersion != nil {
+		args = append(args, "--http"+*config.HTTPVersion)
+	}
+
+	if config.ConnectTimeout != nil {
+		args = append(args, "--connect-timeout", strconv.Itoa(int(*config.ConnectTimeout)))
+	}
+
+	if config.UnixSocket != nil {
+		unixSocket := *config.UnixSocket
+		if escape {
+			unixSocket = curl.shell.Escape(unixSocket)
+		}
+
+		args = append(args, "--unix-socket", unixSocket)
+	}
+
+	return args
+}
+
 func (curl *curl) getBodyArgument() []string {
+	if curl.backendInput.BodyFromStdin {
+		return []string{"--data-binary", "@-"}
+	}
+
 	if curl.backendInput.TempFileName != "" {
-		return []string{"-d", "@" + curl.backendInput.TempFileName}
+		// -d strips newlines from the file
+		return []string{"--data-binary", "@" + curl.backendInput.TempFileName}
 	}
 
 	return []string{}
 }
 
+func (curl *curl) getPrintedBodyArgument() []string {
+	if !curl.backendInput.PrintsInlineBody() {
+		return curl.getBodyArgument()
+	}
+
+	// --data-raw doesn't read a file when the body starts with @
+	return []string{"--data-raw", getInlineBody(curl.backendInput, curl.shell)}
+}
+
+var curlIncludeOptionRe = regexp.MustCompile(`^(--include|-[a-zA-Z]*i[a-zA-Z]*)$`)
+
+func (curl *curl) printsResponseHead() bool {
+	return hasBackendOption(curl.backendInput.BackendOptions, curlIncludeOptionRe.MatchString)
+}
+
+func (curl *curl) getInspectResponseArguments() []string {
+	if !curl.backendInput.InspectResponse || curl.printsResponseHead() {
+		return []string{}
+	}
+
+	return []string{"--include"}
+}
+
 func (curl *curl) getAsCmd(ctx context.Context) *exec.Cmd {
 	args := []string{}
 	for _, backendOpt := range curl.backendInput.BackendOptions {
@@ -67,6 +220,11 @@ func (curl *curl) getAsCmd(ctx context.Context) *exec.Cmd {
 		args = append(args, headerArgs...)
 	}
 
+	args = append(args, curl.getAuthArguments(false)...)
+	args = append(args, curl.getCookieJarArguments(false)...)
+	args = append(args, curl.getConfigArguments(false)...)
+	args = append(args, curl.getInspectResponseArguments()...)
+
 	args = append(args, curl.getBodyArgument()...)
 	args = append(args, curl.backendInput.Host.String())
 
@@ -79,20 +237,23 @@ func (curl *curl) getAsString() string {
 	for _, optionLine := range curl.backendInput.BackendOptions {
 		lineArguments := []string{}
 		for _, option := range optionLine {
-			lineArguments = append(lineArguments, utils.EscapeForShell(option))
+			lineArguments = append(lineArguments, curl.shell.Escape(option))
 		}
 		args = append(args, lineArguments)
 	}
 
 	args = append(args, curl.getMethodArgument(true))
 	args = append(args, curl.getHeaderArguments(true)...)
+	args = append(args, curl.getAuthArguments(true))
+	args = append(args, curl.getCookieJarArguments(true))
+	args = append(args, curl.getConfigArguments(true))
 
-	args = append(args, curl.getBodyArgument())
+	args = append(args, curl.getPrintedBodyArgument())
 	args = append(args, []string{
-		utils.EscapeForShell(curl.backendInput.Host.String()),
+		curl.shell.Escape(curl.backendInput.Host.String()),
 	})
 
-	cmdAsString := curl.binaryName + " " + utils.PrettyPrintStringsForShell(args)
+	cmdAsString := curl.shell.CommandName(curl.binaryName) + " " + curl.shell.PrettyPrint(args)
 
 	return cmdAsString
 }
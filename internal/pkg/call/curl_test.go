@@ -0,0 +1,58 @@
+package call
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os/exec"
+	"testing"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+)
+
+func Test_curlSendsBodyAsIs(t *testing.T) {
+	if _, err := exec.LookPath("curl"); err != nil {
+		t.Skip("curl is not on the $PATH")
+	}
+
+	var receivedBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedBody, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	host, _ := url.Parse(server.URL)
+
+	// curl -d would strip the newlines and carriage returns
+	backendInput := &data.BackendInput{Host: host, Body: []string{"{", "", "  \"a\": 1\r", "}"}}
+	if err := backendInput.CreateBodyTempFile(); err != nil {
+		t.Fatal(err)
+	}
+	defer backendInput.RemoveBodyTempFile(true)
+
+	if err := newCurlBackend(backendInput, "curl").getAsCmd(context.Background()).Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := backendInput.GetBody(); string(receivedBody) != expected {
+		t.Errorf("Test: body, Expected: %q, Got: %q", expected, receivedBody)
+	}
+}
+
+func Test_curlPrintsSessionTokenMasked(t *testing.T) {
+	host, _ := url.Parse("http://localhost:8080")
+	backendInput := &data.BackendInput{Host: host, Headers: []string{"X-Amz-Date: 20150830T123600Z", "X-Amz-Security-Token: FQoGZXIvYXdzE"}}
+
+	expected := "curl -H 'X-Amz-Date: 20150830T123600Z' \\\n  -H 'X-Amz-Security-Token: ********' \\\n  'http://localhost:8080'"
+	if got := newCurlBackend(backendInput, "curl").getAsString(); got != expected {
+		t.Errorf("Test: printed, Expected: %s, Got: %s", expected, got)
+	}
+
+	// The token is sent when the command is run
+	args := newCurlBackend(backendInput, "curl").getAsCmd(context.Background()).Args
+	if args[4] != "X-Amz-Security-Token: FQoGZXIvYXdzE" {
+		t.Errorf("Test: run, Expected: the session token, Got: %v", args)
+	}
+}
@@ -0,0 +1,152 @@
+package call
+
+import (
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+)
+
+type goPrinter struct {
+	backendInput *data.BackendInput
+}
+
+func newGoPrinter(backendInput *data.BackendInput) printer {
+	return &goPrinter{backendInput: backendInput}
+}
+
+var goTranslatedConfig = map[string]bool{"Timeout": true, "Insecure": true, "FollowRedirects": true}
+
+// quoteGoBody uses a raw string literal when
+// it's the same as the body, it's easier to edit
+func quoteGoBody(body string) string {
+	if !strings.ContainsAny(body, "`\r") {
+		return "`" + body + "`"
+	}
+
+	return strings.Join(quoteBodyLines(body, strconv.Quote), " +\n\t\t")
+}
+
+func (g *goPrinter) getClientFields() ([]string, []string) {
+	config := g.backendInput.Config
+	imports := []string{}
+	clientFields := []string{}
+
+	if config.Timeout != data.TimeoutNotSet {
+		imports = append(imports, "time")
+		clientFields = append(clientFields, "Timeout: "+strconv.Itoa(int(config.Timeout))+" * time.Second,")
+	}
+
+	if data.IsTrue(config.Insecure) {
+		imports = append(imports, "crypto/tls")
+		clientFields = append(clientFields, "Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},")
+	}
+
+	if config.FollowRedirects != nil && !*config.FollowRedirects {
+		clientFields = append(clientFields,
+			"CheckRedirect: func(req *http.Request, via []*http.Request) error {",
+			"\treturn http.ErrUseLastResponse",
+			"},",
+		)
+	}
+
+	return imports, clientFields
+}
+
+func (g *goPrinter) getAsString() string {
+	var builder strings.Builder
+
+	auth := getAuth(g.backendInput, true)
+	untranslated := getUntranslated(g.backendInput, goTranslatedConfig)
+	if auth != nil && auth.Scheme == data.AuthDigest {
+		untranslated = append(untranslated, "[Auth] digest")
+	}
+
+	writeUntranslated(&builder, "//", untranslated)
+
+	imports := []string{"fmt", "io", "net/http"}
+	clientImports, clientFields := g.getClientFields()
+	imports = append(imports, clientImports...)
+
+	body := "nil"
+	if len(g.backendInput.Body) > 0 {
+		imports = append(imports, "strings")
+		body = "body"
+	}
+
+	sort.Strings(imports)
+
+	builder.WriteString("package main\n\nimport (\n")
+	for _, goImport := range imports {
+		builder.WriteString("\t" + strconv.Quote(goImport) + "\n")
+	}
+
+	builder.WriteString(")\n\nfunc main() {\n")
+	if body != "nil" {
+		builder.WriteString("\tbody := strings.NewReader(" + quoteGoBody(g.backendInput.GetBody()) + ")\n")
+	}
+
+	builder.WriteString("\treq, err := http.NewRequest(" + strconv.Quote(g.backendInput.GetMethod()) + ", " + strconv.Quote(g.backendInput.Host.String()) + ", " + body + ")\n")
+	builder.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n\n")
+
+	headers := getPrinterHeaders(getHeaders(g.backendInput, true))
+	for _, header := range headers {
+		// net/http ignores a Host header, it's sent from req.Host
+		if strings.EqualFold(header.name, "Host") {
+			builder.WriteString("\treq.Host = " + strconv.Quote(header.value) + "\n")
+			continue
+		}
+
+		builder.WriteString("\treq.Header.Set(" + strconv.Quote(header.name) + ", " + strconv.Quote(header.value) + ")\n")
+	}
+
+	if auth != nil {
+		switch auth.Scheme {
+		case data.AuthBasic:
+			builder.WriteString("\treq.SetBasicAuth(" + strconv.Quote(auth.Username) + ", " + strconv.Quote(auth.Password) + ")\n")
+		case data.AuthBearer:
+			builder.WriteString("\treq.Header.Set(\"Authorization\", " + strconv.Quote("Bearer "+auth.Token) + ")\n")
+		}
+	}
+
+	if len(headers) > 0 || (auth != nil && auth.Scheme != data.AuthDigest) {
+		builder.WriteString("\n")
+	}
+
+	if len(clientFields) == 0 {
+		builder.WriteString("\tclient := &http.Client{}\n")
+	} else {
+		builder.WriteString("\tclient := &http.Client{\n")
+		for _, clientField := range clientFields {
+			builder.WriteString("\t\t" + clientField + "\n")
+		}
+
+		builder.WriteString("\t}\n")
+	}
+
+	builder.WriteString(`
+	resp, err := client.Do(req)
+	if err != nil {
+		panic(err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Print(string(respBody))
+}
+`)
+
+	// Aligns the client fields the same as gofmt
+	formatted, err := format.Source([]byte(builder.String()))
+	if err != nil {
+		return builder.String()
+	}
+
+	return string(formatted)
+}
@@ -0,0 +1,178 @@
+package call
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+)
+
+// The backends don't tell, so both are assumed to be HTTP/1.1
+const harHTTPVersion = "HTTP/1.1"
+
+func getHARHeaders(headers []string) []data.HARNameValue {
+	harHeaders := []data.HARNameValue{}
+
+	for _, header := range headers {
+		headerParts := strings.SplitN(header, ":", 2)
+		if len(headerParts) != 2 {
+			continue
+		}
+
+		harHeaders = append(harHeaders, data.HARNameValue{
+			Name:  strings.TrimSpace(headerParts[0]),
+			Value: strings.TrimSpace(headerParts[1]),
+		})
+	}
+
+	return harHeaders
+}
+
+// getHARCookies splits the cookies in the Cookie (request)
+// or Set-Cookie (response) headers into name and value
+func getHARCookies(headers []string, cookieHeaderName string) []data.HARNameValue {
+	harCookies := []data.HARNameValue{}
+
+	for _, header := range getHARHeaders(headers) {
+		if !strings.EqualFold(header.Name, cookieHeaderName) {
+			continue
+		}
+
+		cookies := strings.Split(header.Value, ";")
+		if cookieHeaderName == "Set-Cookie" {
+			// The rest are the cookie attributes
+			cookies = cookies[:1]
+		}
+
+		for _, cookie := range cookies {
+			cookieParts := strings.SplitN(strings.TrimSpace(cookie), "=", 2)
+			if len(cookieParts) == 2 {
+				harCookies = append(harCookies, data.HARNameValue{Name: cookieParts[0], Value: cookieParts[1]})
+			}
+		}
+	}
+
+	return harCookies
+}
+
+func getHARQueryString(hostUrl *url.URL) []data.HARNameValue {
+	harQueryString := []data.HARNameValue{}
+
+	if hostUrl.RawQuery == "" {
+		return harQueryString
+	}
+
+	// Keeps the order, which url.Query() doesn't
+	for _, queryParameter := range strings.Split(hostUrl.RawQuery, "&") {
+		nameAndValue := strings.SplitN(queryParameter, "=", 2)
+		name, _ := url.QueryUnescape(nameAndValue[0])
+		value := ""
+		if len(nameAndValue) == 2 {
+			value, _ = url.QueryUnescape(nameAndValue[1])
+		}
+
+		harQueryString = append(harQueryString, data.HARNameValue{Name: name, Value: value})
+	}
+
+	return harQueryString
+}
+
+func getHARRequest(backendInput *data.BackendInput) data.HARRequest {
+	headers := backendInput.Headers
+	if backendInput.Auth != nil {
+		if authHeader, ok := backendInput.Auth.Header(); ok {
+			headers = append(append([]string{}, headers...), authHeader)
+		}
+	}
+
+	harRequest := data.HARRequest{
+		Method:      backendInput.GetMethod(),
+		Url:         backendInput.Host.String(),
+		HTTPVersion: harHTTPVersion,
+		Cookies:     getHARCookies(headers, "Cookie"),
+		Headers:     getHARHeaders(headers),
+		QueryString: getHARQueryString(backendInput.Host),
+		HeadersSize: -1,
+		BodySize:    0,
+	}
+
+	if len(backendInput.Body) > 0 {
+		body := backendInput.GetBody()
+		contentType, _ := GetHeader(backendInput.Headers, "Content-Type")
+
+		harRequest.PostData = &data.HARPostData{MimeType: contentType, Text: body}
+		harRequest.BodySize = len(body)
+	}
+
+	return harRequest
+}
+
+func getHARContent(body string, headers []string) data.HARContent {
+	contentType, _ := GetHeader(headers, "Content-Type")
+	harContent := data.HARContent{Size: len(body), MimeType: contentType}
+
+	if utf8.ValidString(body) {
+		harContent.Text = body
+	} else {
+		harContent.Text = base64.StdEncoding.EncodeToString([]byte(body))
+		harContent.Encoding = "base64"
+	}
+
+	return harContent
+}
+
+// GetHARRequestEntry returns the request as a HAR entry
+// without a response, the status is then 0. It needs no
+// backend, so it's what's exported when not calling.
+func GetHARRequestEntry(backendInput *data.BackendInput, started time.Time) data.HAREntry {
+	return data.HAREntry{
+		StartedDateTime: started.Format(time.RFC3339Nano),
+		Request:         getHARRequest(backendInput),
+		Response: data.HARResponse{
+			HTTPVersion: harHTTPVersion,
+			Cookies:     []data.HARNameValue{},
+			Headers:     []data.HARNameValue{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+	}
+}
+
+// GetHAREntry returns the request and any response as a HAR entry.
+// Without a response (no answer) the status is 0.
+func (c *Call) GetHAREntry(backendOutput *data.BackendOutput, started time.Time) data.HAREntry {
+	harEntry := GetHARRequestEntry(c.backendInput, started)
+
+	if backendOutput == nil || backendOutput.StatusCode == 0 {
+		return harEntry
+	}
+
+	body := backendOutput.Stdout
+	if c.backend.printsResponseHead() {
+		_, body, _ = splitResponseHead(body)
+	}
+
+	durationMs := float64(backendOutput.Duration.Microseconds()) / 1000
+	harEntry.Time = durationMs
+	harEntry.Timings.Wait = durationMs
+
+	response := &harEntry.Response
+	response.Status = backendOutput.StatusCode
+	if statusParts := strings.SplitN(backendOutput.Status, " ", 2); len(statusParts) == 2 {
+		response.StatusText = statusParts[1]
+	}
+
+	response.Cookies = getHARCookies(backendOutput.Headers, "Set-Cookie")
+	response.Headers = getHARHeaders(backendOutput.Headers)
+	response.Content = getHARContent(body, backendOutput.Headers)
+	response.BodySize = len(body)
+
+	if location, found := GetHeader(backendOutput.Headers, "Location"); found {
+		response.RedirectUrl = location
+	}
+
+	return harEntry
+}
@@ -0,0 +1,112 @@
+package call
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+)
+
+func Test_getHARQueryString(t *testing.T) {
+	tests := map[string]struct {
+		rawUrl   string
+		expected []data.HARNameValue
+	}{
+		"No query": {
+			rawUrl:   "http://localhost/",
+			expected: []data.HARNameValue{},
+		},
+		"Order kept and unescaped": {
+			rawUrl:   "http://localhost/?b=2&a=x%20y&flag",
+			expected: []data.HARNameValue{{Name: "b", Value: "2"}, {Name: "a", Value: "x y"}, {Name: "flag", Value: ""}},
+		},
+	}
+
+	for name, test := range tests {
+		hostUrl, _ := url.Parse(test.rawUrl)
+		if got := getHARQueryString(hostUrl); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("Test: %s, Expected: %v, Got: %v", name, test.expected, got)
+		}
+	}
+}
+
+func Test_getHARCookies(t *testing.T) {
+	tests := map[string]struct {
+		headers          []string
+		cookieHeaderName string
+		expected         []data.HARNameValue
+	}{
+		"Request cookies": {
+			headers:          []string{"Accept: */*", "Cookie: theme=dark; session=abc"},
+			cookieHeaderName: "Cookie",
+			expected:         []data.HARNameValue{{Name: "theme", Value: "dark"}, {Name: "session", Value: "abc"}},
+		},
+		"Response cookies without attributes": {
+			headers:          []string{"Set-Cookie: session=abc; Path=/; HttpOnly", "set-cookie: theme=dark"},
+			cookieHeaderName: "Set-Cookie",
+			expected:         []data.HARNameValue{{Name: "session", Value: "abc"}, {Name: "theme", Value: "dark"}},
+		},
+	}
+
+	for name, test := range tests {
+		if got := getHARCookies(test.headers, test.cookieHeaderName); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("Test: %s, Expected: %v, Got: %v", name, test.expected, got)
+		}
+	}
+}
+
+func TestCall_GetHAREntry(t *testing.T) {
+	hostUrl, _ := url.Parse("http://localhost:8080/users?page=2")
+	backendInput := &data.BackendInput{
+		Host:    hostUrl,
+		Method:  "POST",
+		Headers: []string{"Content-Type: application/json"},
+		Auth:    &data.Auth{Scheme: data.AuthBearer, Token: "abc"},
+		Body:    []string{`{"name": "goat"}`},
+		Backend: "curl",
+	}
+
+	started := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// Not called needs no backend set up
+	notCalled := GetHARRequestEntry(backendInput, started)
+	if notCalled.StartedDateTime != "2024-01-02T03:04:05Z" || notCalled.Response.Status != 0 || notCalled.Response.BodySize != -1 {
+		t.Errorf("Test: Not called, Expected: empty response, Got: %+v", notCalled.Response)
+	}
+
+	expectedHeaders := []data.HARNameValue{{Name: "Content-Type", Value: "application/json"}, {Name: "Authorization", Value: "Bearer abc"}}
+	request := notCalled.Request
+	if request.Method != "POST" || request.Url != "http://localhost:8080/users?page=2" ||
+		!reflect.DeepEqual(request.Headers, expectedHeaders) ||
+		request.PostData == nil || request.PostData.Text != `{"name": "goat"}` || request.PostData.MimeType != "application/json" {
+		t.Errorf("Test: Request, Expected: method, url, headers and post data, Got: %+v %+v", request, request.PostData)
+	}
+
+	backendCall, err := Setup(backendInput)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	defer backendCall.Teardown()
+
+	called := backendCall.GetHAREntry(&data.BackendOutput{
+		Stdout:     "created",
+		StatusCode: 201,
+		Status:     "201 Created",
+		Headers:    []string{"Content-Type: text/plain", "Location: /users/1"},
+		Duration:   1500 * time.Microsecond,
+	}, started)
+
+	response := called.Response
+	if response.Status != 201 || response.StatusText != "Created" || response.RedirectUrl != "/users/1" ||
+		response.Content.Text != "created" || response.Content.MimeType != "text/plain" || called.Time != 1.5 {
+		t.Errorf("Test: Called, Expected: status, body and time, Got: %+v %v", response, called.Time)
+	}
+
+	binary := backendCall.GetHAREntry(&data.BackendOutput{Stdout: "\xff\xfe", StatusCode: 200, Status: "200 OK"}, started)
+	if binary.Response.Content.Encoding != "base64" || binary.Response.Content.Text != "//4=" {
+		t.Errorf("Test: Binary body, Expected: base64 //4=, Got: %+v", binary.Response.Content)
+	}
+}
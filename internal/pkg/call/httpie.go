@@ -2,7 +2,11 @@ package call
 
 import (
 	"context"
+	"fmt"
+	"net/url"
 	"os/exec"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/jonaslu/ain/internal/pkg/data"
@@ -12,6 +16,7 @@ import (
 type httpie struct {
 	backendInput *data.BackendInput
 	binaryName   string
+	shell        utils.Shell
 }
 
 func prependIgnoreStdin(backendInput *data.BackendInput) {
@@ -32,10 +37,15 @@ func prependIgnoreStdin(backendInput *data.BackendInput) {
 }
 
 func newHttpieBackend(backendInput *data.BackendInput, binaryName string) backend {
-	prependIgnoreStdin(backendInput)
+	// Httpie reads the body from stdin on it's own
+	if !backendInput.BodyFromStdin && !backendInput.PrintsInlineBody() {
+		prependIgnoreStdin(backendInput)
+	}
+
 	return &httpie{
 		backendInput: backendInput,
 		binaryName:   binaryName,
+		shell:        utils.GetShell(backendInput.PrintShell),
 	}
 }
 
@@ -43,6 +53,225 @@ func (httpie *httpie) getMethodArgument() string {
 	return strings.ToUpper(httpie.backendInput.Method)
 }
 
+func (httpie *httpie) getAuthArguments(escape bool) []string {
+	auth := getAuth(httpie.backendInput, escape)
+	if auth == nil {
+		return []string{}
+	}
+
+	usernameAndPassword := auth.UsernameAndPassword()
+	token := auth.Token
+	if escape {
+		usernameAndPassword = httpie.shell.Escape(usernameAndPassword)
+		token = httpie.shell.Escape(token)
+	}
+
+	switch auth.Scheme {
+	case data.AuthBasic:
+		return []string{"-a", usernameAndPassword}
+	case data.AuthDigest:
+		return []string{"-A", "digest", "-a", usernameAndPassword}
+	case data.AuthBearer:
+		return []string{"-A", "bearer", "-a", token}
+	}
+
+	return []string{}
+}
+
+func (httpie *httpie) getCookieJarArguments(escape bool) []string {
+	cookieJar := httpie.backendInput.Config.CookieJar
+	if cookieJar == nil {
+		return []string{}
+	}
+
+	// Httpie treats a session without a path separator as
+	// a named session in its config dir instead of a file
+	cookieJarFilename := *cookieJar
+	if !strings.ContainsAny(cookieJarFilename, `/\`) {
+		cookieJarFilename = "./" + cookieJarFilename
+	}
+
+	if escape {
+		cookieJarFilename = httpie.shell.Escape(cookieJarFilename)
+	}
+
+	return []string{"--session=" + cookieJarFilename}
+}
+
+func (httpie *httpie) getConfigArguments(escape bool) []string {
+	config := httpie.backendInput.Config
+	args := []string{}
+
+	if config.Proxy != nil {
+		// Httpie sets the proxy per protocol
+		httpProxy := "http:" + *config.Proxy
+		httpsProxy := "https:" + *config.Proxy
+		if escape {
+			httpProxy = httpie.shell.Escape(httpProxy)
+			httpsProxy = httpie.shell.Escape(httpsProxy)
+		}
+
+		args = append(args, "--proxy="+httpProxy, "--proxy="+httpsProxy)
+	}
+
+	// Both are set with --verify, turning verification off wins
+	if data.IsTrue(config.Insecure) {
+		args = append(args, "--verify=no")
+	} else if config.CACert != nil {
+		caCert := *config.CACert
+		if escape {
+			caCert = httpie.shell.Escape(caCert)
+		}
+
+		args = append(args, "--verify="+caCert)
+	}
+
+	if config.ClientCert != nil {
+		clientCert := *config.ClientCert
+		if escape {
+			clientCert = httpie.shell.Escape(clientCert)
+		}
+
+		args = append(args, "--cert="+clientCert)
+	}
+
+	if config.ClientKey != nil {
+		clientKey := *config.ClientKey
+		if escape {
+			clientKey = httpie.shell.Escape(clientKey)
+		}
+
+		args = append(args, "--cert-key="+clientKey)
+	}
+
+	if config.FollowsRedirects() {
+		args = append(args, "--follow")
+	}
+
+	if config.MaxRedirects != nil {
+		args = append(args, "--max-redirects="+strconv.Itoa(int(*config.MaxRedirects)))
+	}
+
+	if data.IsTrue(config.Verbose) {
+		args = append(args, "--verbose")
+	}
+
+	// Httpie always asks for and decompresses compressed
+	// responses, so there's nothing to add for Compressed
+
+	if config.ConnectTimeout != nil {
+		// Also limits the time waiting for the response
+		args = append(args, "--timeout="+strconv.Itoa(int(*config.ConnectTimeout)))
+	}
+
+	return args
+}
+
+// getHostArgument returns the url to call. Httpie calls unix sockets
+// via the httpie-unixsocket plugin, where the url host is the
+// socket path escaped.
+func (httpie *httpie) getHostArgument() string {
+	host := httpie.backendInput.Host
+	unixSocket := httpie.backendInput.Config.UnixSocket
+
+	if unixSocket == nil {
+		return host.String()
+	}
+
+	unixSocketHost := *host
+	unixSocketHost.Scheme = ""
+	unixSocketHost.Host = ""
+
+	return "http+unix://" + url.PathEscape(*unixSocket) + unixSocketHost.String()
+}
+
+// Options choosing what httpie prints, which --print=hb would override.
+// A head printed this way is kept in the output.
+var httpiePrintOptionRe = regexp.MustCompile(`^(-h|--headers|-b|--body|-m|--meta|-v|--verbose|--all|-p.*|--print(=.*)?)$`)
+
+func (httpie *httpie) printsResponseHead() bool {
+	return hasBackendOption(httpie.backendInput.BackendOptions, httpiePrintOptionRe.MatchString)
+}
+
+func (httpie *httpie) getInspectResponseArguments() []string {
+	if !httpie.backendInput.InspectResponse || httpie.printsResponseHead() {
+		return []string{}
+	}
+
+	return []string{"--print=hb"}
+}
+
+func httpieUnsupportedConfig(config data.Config) []string {
+	unsupportedConfig := []string{}
+
+	if config.HTTPVersion != nil && *config.HTTPVersion != "1.1" {
+		unsupportedConfig = append(unsupportedConfig, fmt.Sprintf("Backend httpie only supports HTTPVersion=1.1, got HTTPVersion=%s", *config.HTTPVersion))
+	}
+
+	return unsupportedConfig
+}
+
+// getHttpieSessionHeaderNames returns the names of the headers httpie
+// saves in a session. Cookies go into the jar as intended, and httpie
+// leaves out headers starting with Content- or If-.
+func getHttpieSessionHeaderNames(headers []string) []string {
+	headerNames := []string{}
+	seenHeaderNames := map[string]bool{}
+
+	for _, header := range headers {
+		headerName, _, found := strings.Cut(header, ":")
+		if !found {
+			continue
+		}
+
+		headerName = strings.TrimSpace(headerName)
+		lowerHeaderName := strings.ToLower(headerName)
+		if lowerHeaderName == "cookie" || strings.HasPrefix(lowerHeaderName, "content-") || strings.HasPrefix(lowerHeaderName, "if-") || seenHeaderNames[lowerHeaderName] {
+			continue
+		}
+
+		seenHeaderNames[lowerHeaderName] = true
+		headerNames = append(headerNames, headerName)
+	}
+
+	return headerNames
+}
+
+// HttpieSessionWarning returns a warning when the httpie session used as the
+// cookie jar also saves the auth or headers, later calls using the same file
+// then send them. Call it before [Sign] adds its headers.
+func HttpieSessionWarning(backendInput *data.BackendInput) string {
+	if backendInput.Backend != "httpie" || backendInput.Config.CookieJar == nil {
+		return ""
+	}
+
+	authSections := []string{}
+	if backendInput.OAuth2 != nil {
+		authSections = append(authSections, "[OAuth2]")
+	} else if backendInput.Auth != nil {
+		authSections = append(authSections, "[Auth]")
+	}
+
+	if backendInput.Sign != nil {
+		authSections = append(authSections, "[Sign]")
+	}
+
+	saved := []string{}
+	if len(authSections) > 0 {
+		saved = append(saved, strings.Join(authSections, " and ")+" credentials")
+	}
+
+	if headerNames := getHttpieSessionHeaderNames(backendInput.Headers); len(headerNames) > 0 {
+		saved = append(saved, "[Headers] "+strings.Join(headerNames, ", "))
+	}
+
+	if len(saved) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("Warning: httpie saves the %s in the CookieJar=%s session, later calls using the same CookieJar send them too", strings.Join(saved, " and "), *backendInput.Config.CookieJar)
+}
+
 func (httpie *httpie) getBodyArgument() []string {
 	if httpie.backendInput.TempFileName != "" {
 		return []string{"@" + httpie.backendInput.TempFileName}
@@ -57,11 +286,16 @@ func (httpie *httpie) getAsCmd(ctx context.Context) *exec.Cmd {
 		args = append(args, backendOpt...)
 	}
 
+	args = append(args, httpie.getAuthArguments(false)...)
+	args = append(args, httpie.getCookieJarArguments(false)...)
+	args = append(args, httpie.getConfigArguments(false)...)
+	args = append(args, httpie.getInspectResponseArguments()...)
+
 	if httpie.backendInput.Method != "" {
 		args = append(args, httpie.getMethodArgument())
 	}
 
-	args = append(args, httpie.backendInput.Host.String())
+	args = append(args, httpie.getHostArgument())
 	args = append(args, httpie.backendInput.Headers...)
 	args = append(args, httpie.getBodyArgument()...)
 
@@ -74,24 +308,36 @@ func (httpie *httpie) getAsString() string {
 	for _, optionLine := range httpie.backendInput.BackendOptions {
 		lineArguments := []string{}
 		for _, option := range optionLine {
-			lineArguments = append(lineArguments, utils.EscapeForShell(option))
+			lineArguments = append(lineArguments, httpie.shell.Escape(option))
 		}
 		args = append(args, lineArguments)
 	}
 
+	args = append(args, httpie.getAuthArguments(true))
+	args = append(args, httpie.getCookieJarArguments(true))
+	args = append(args, httpie.getConfigArguments(true))
+
 	if httpie.backendInput.Method != "" {
-		args = append(args, []string{utils.EscapeForShell(httpie.getMethodArgument())})
+		args = append(args, []string{httpie.shell.Escape(httpie.getMethodArgument())})
 	}
 
-	args = append(args, []string{utils.EscapeForShell(httpie.backendInput.Host.String())})
+	args = append(args, []string{httpie.shell.Escape(httpie.getHostArgument())})
 
-	for _, header := range httpie.backendInput.Headers {
-		args = append(args, []string{utils.EscapeForShell(header)})
+	for _, header := range getHeaders(httpie.backendInput, true) {
+		args = append(args, []string{httpie.shell.Escape(header)})
 	}
 
-	args = append(args, httpie.getBodyArgument())
+	inlineBody := httpie.backendInput.PrintsInlineBody()
+	if !inlineBody {
+		args = append(args, httpie.getBodyArgument())
+	}
 
-	output := httpie.binaryName + " " + utils.PrettyPrintStringsForShell(args)
+	output := httpie.shell.CommandName(httpie.binaryName) + " " + httpie.shell.PrettyPrint(args)
+
+	// Httpie reads an inline body from stdin
+	if inlineBody {
+		output = "printf '%s' " + getInlineBody(httpie.backendInput, httpie.shell) + " | " + output
+	}
 
 	return output
 }
@@ -0,0 +1,66 @@
+package call
+
+import (
+	"github.com/jonaslu/ain/internal/pkg/data"
+	"github.com/jonaslu/ain/internal/pkg/utils"
+	"github.com/pkg/errors"
+)
+
+// Bodies this long are printed as a here-document,
+// it's easier to read than one long quoted argument
+const heredocMinBodyLines = 10
+
+const heredocDelimiter = "AIN_BODY"
+
+// getInlineBody returns the body as one argument for the shell. The here-document
+// delimiter is quoted so the shell leaves any $ or ` in the body alone. The command
+// substitution drops every trailing newline, so a body ending with a newline (e g
+// piped with --body-stdin) is quoted as is instead.
+func getInlineBody(backendInput *data.BackendInput, shell utils.Shell) string {
+	endsWithNewline := len(backendInput.Body) > 0 && backendInput.Body[len(backendInput.Body)-1] == ""
+	if len(backendInput.Body) < heredocMinBodyLines || !shell.HereDocuments || endsWithNewline {
+		return shell.Escape(backendInput.GetBody())
+	}
+
+	delimiter := heredocDelimiter
+	for containsLine(backendInput.Body, delimiter) {
+		delimiter += "_"
+	}
+
+	return `"$(cat <<'` + delimiter + "'\n" + backendInput.GetBody() + "\n" + delimiter + "\n" + `)"`
+}
+
+func containsLine(lines []string, line string) bool {
+	for _, l := range lines {
+		if l == line {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkInlineBodyShell returns an error if the printed
+// command with the body cannot be pasted into the shell
+func checkInlineBodyShell(backendInput *data.BackendInput) error {
+	if !backendInput.PrintsInlineBody() {
+		return nil
+	}
+
+	shellName := backendInput.PrintShell
+	if shellName == "" {
+		shellName = utils.DefaultShell
+	}
+
+	shell := utils.GetShell(shellName)
+
+	if len(backendInput.Body) > 1 && !shell.MultilineArguments {
+		return errors.Errorf("%s cannot have a body over several lines in the command, leave out --inline-body", shellName)
+	}
+
+	if backendInput.Backend == "httpie" && !shell.PipesPrintf {
+		return errors.Errorf("httpie reads the body from a pipe which %s cannot print as is, leave out --inline-body", shellName)
+	}
+
+	return nil
+}
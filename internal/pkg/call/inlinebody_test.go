@@ -0,0 +1,37 @@
+package call
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+	"github.com/jonaslu/ain/internal/pkg/utils"
+)
+
+func Test_getInlineBody(t *testing.T) {
+	longBody := strings.Split(strings.Repeat("line\n", heredocMinBodyLines-1)+"AIN_BODY", "\n")
+
+	tests := map[string]struct {
+		body     []string
+		expected string
+	}{
+		"Quoted": {
+			body:     []string{`{"it's": "$HOME"}`},
+			expected: `'{"it'"'"'s": "$HOME"}'`,
+		},
+		"Ends with newlines": {
+			body:     append(append([]string{}, longBody...), "", ""),
+			expected: "'" + strings.Join(longBody, "\n") + "\n\n'",
+		},
+		"Here-document with a delimiter not in the body": {
+			body:     longBody,
+			expected: "\"$(cat <<'AIN_BODY_'\n" + strings.Join(longBody, "\n") + "\nAIN_BODY_\n)\"",
+		},
+	}
+
+	for name, test := range tests {
+		if got := getInlineBody(&data.BackendInput{Body: test.body}, utils.GetShell(utils.DefaultShell)); got != test.expected {
+			t.Errorf("Test: %s, Expected: %s, Got: %s", name, test.expected, got)
+		}
+	}
+}
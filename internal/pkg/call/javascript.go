@@ -0,0 +1,81 @@
+package call
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+)
+
+type javascriptPrinter struct {
+	backendInput *data.BackendInput
+}
+
+func newJavascriptPrinter(backendInput *data.BackendInput) printer {
+	return &javascriptPrinter{backendInput: backendInput}
+}
+
+var javascriptTranslatedConfig = map[string]bool{"Timeout": true, "FollowRedirects": true}
+
+// getHeaderLines returns the headers as object properties,
+// the value is an expression for basic auth
+func (j *javascriptPrinter) getHeaderLines(auth *data.Auth) []string {
+	headerLines := []string{}
+	for _, header := range getPrinterHeaders(getHeaders(j.backendInput, true)) {
+		headerLines = append(headerLines, quoteString(header.name)+": "+quoteString(header.value)+",")
+	}
+
+	if auth != nil {
+		switch auth.Scheme {
+		case data.AuthBasic:
+			headerLines = append(headerLines, `"Authorization": "Basic " + btoa(`+quoteString(auth.UsernameAndPassword())+"),")
+		case data.AuthBearer:
+			headerLines = append(headerLines, `"Authorization": `+quoteString("Bearer "+auth.Token)+",")
+		}
+	}
+
+	return headerLines
+}
+
+func (j *javascriptPrinter) getAsString() string {
+	var builder strings.Builder
+
+	config := j.backendInput.Config
+	auth := getAuth(j.backendInput, true)
+
+	untranslated := getUntranslated(j.backendInput, javascriptTranslatedConfig)
+	if auth != nil && auth.Scheme == data.AuthDigest {
+		untranslated = append(untranslated, "[Auth] digest")
+	}
+
+	writeUntranslated(&builder, "//", untranslated)
+
+	builder.WriteString("const response = await fetch(" + quoteString(j.backendInput.Host.String()) + ", {\n")
+	builder.WriteString("  method: " + quoteString(j.backendInput.GetMethod()) + ",\n")
+
+	if headerLines := j.getHeaderLines(auth); len(headerLines) > 0 {
+		builder.WriteString("  headers: {\n")
+		for _, headerLine := range headerLines {
+			builder.WriteString("    " + headerLine + "\n")
+		}
+
+		builder.WriteString("  },\n")
+	}
+
+	if len(j.backendInput.Body) > 0 {
+		builder.WriteString("  body:\n    " + strings.Join(quoteBodyLines(j.backendInput.GetBody(), quoteString), " +\n    ") + ",\n")
+	}
+
+	if config.Timeout != data.TimeoutNotSet {
+		builder.WriteString("  signal: AbortSignal.timeout(" + strconv.Itoa(int(config.Timeout)*1000) + "),\n")
+	}
+
+	if config.FollowRedirects != nil && !*config.FollowRedirects {
+		builder.WriteString("  redirect: \"manual\",\n")
+	}
+
+	builder.WriteString("});\n\n")
+	builder.WriteString("console.log(await response.text());\n")
+
+	return builder.String()
+}
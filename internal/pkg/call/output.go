@@ -0,0 +1,140 @@
+package call
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"mime"
+	"net/textproto"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+	"github.com/pkg/errors"
+)
+
+const OutputFormatJSON = "json"
+
+const (
+	bodyEncodingJSON   = "json"
+	bodyEncodingText   = "text"
+	bodyEncodingBase64 = "base64"
+)
+
+type jsonRequest struct {
+	Backend string              `json:"backend"`
+	Method  string              `json:"method"`
+	Url     string              `json:"url"`
+	Headers map[string][]string `json:"headers"`
+}
+
+type jsonOutput struct {
+	Request      jsonRequest         `json:"request"`
+	Status       *int                `json:"status"`
+	Reason       string              `json:"reason"`
+	Headers      map[string][]string `json:"headers"`
+	Body         interface{}         `json:"body"`
+	BodyEncoding string              `json:"bodyEncoding"`
+	DurationMs   int64               `json:"durationMs"`
+}
+
+// getHeadersAsMap keys the headers on their canonical name. Repeated
+// headers keep each value, joining them would break Set-Cookie.
+func getHeadersAsMap(headers []string) map[string][]string {
+	headersMap := map[string][]string{}
+
+	for _, header := range headers {
+		headerParts := strings.SplitN(header, ":", 2)
+		if len(headerParts) != 2 {
+			continue
+		}
+
+		headerName := textproto.CanonicalMIMEHeaderKey(strings.TrimSpace(headerParts[0]))
+		headerValue := strings.TrimSpace(headerParts[1])
+
+		headersMap[headerName] = append(headersMap[headerName], headerValue)
+	}
+
+	return headersMap
+}
+
+// getRequestHeaders returns the headers sent including the
+// Authorization from [Auth], which the backend adds on its own.
+// All credentials are masked the same as with -p.
+func getRequestHeaders(backendInput *data.BackendInput) []string {
+	requestHeaders := []string{}
+	for _, header := range getHeaders(backendInput, true) {
+		requestHeaders = append(requestHeaders, data.MaskAuthorizationHeader(header))
+	}
+
+	if backendInput.Auth != nil {
+		requestHeaders = append(requestHeaders, backendInput.Auth.MaskedHeader())
+	}
+
+	return requestHeaders
+}
+
+func isJSONContentType(headers []string) bool {
+	contentType, found := GetHeader(headers, "Content-Type")
+	if !found {
+		return false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// getBodyAndEncoding returns a json body as is, otherwise
+// as a string or base64 if it's not valid utf-8
+func getBodyAndEncoding(body string, headers []string) (interface{}, string) {
+	if isJSONContentType(headers) && json.Valid([]byte(body)) {
+		return json.RawMessage(body), bodyEncodingJSON
+	}
+
+	if utf8.ValidString(body) {
+		return body, bodyEncodingText
+	}
+
+	return base64.StdEncoding.EncodeToString([]byte(body)), bodyEncodingBase64
+}
+
+// OutputAsJSON returns the request and the response
+// from the backend call as one JSON object
+func (c *Call) OutputAsJSON(backendOutput *data.BackendOutput) (string, error) {
+	body := backendOutput.Stdout
+	if c.backend.printsResponseHead() {
+		_, body, _ = splitResponseHead(body)
+	}
+
+	output := jsonOutput{
+		Request: jsonRequest{
+			Backend: c.backendInput.Backend,
+			Method:  c.backendInput.GetMethod(),
+			Url:     c.backendInput.Host.String(),
+			Headers: getHeadersAsMap(getRequestHeaders(c.backendInput)),
+		},
+		Headers:    getHeadersAsMap(backendOutput.Headers),
+		DurationMs: backendOutput.Duration.Milliseconds(),
+	}
+
+	if backendOutput.StatusCode != 0 {
+		output.Status = &backendOutput.StatusCode
+
+		statusParts := strings.SplitN(backendOutput.Status, " ", 2)
+		if len(statusParts) == 2 {
+			output.Reason = statusParts[1]
+		}
+	}
+
+	output.Body, output.BodyEncoding = getBodyAndEncoding(body, backendOutput.Headers)
+
+	outputBytes, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", errors.Wrap(err, "could not format the output as JSON")
+	}
+
+	return string(outputBytes) + "\n", nil
+}
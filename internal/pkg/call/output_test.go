@@ -0,0 +1,101 @@
+package call
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+)
+
+func Test_getHeadersAsMap(t *testing.T) {
+	headers := []string{"content-type: application/json", "Set-Cookie: a=1", "Set-Cookie: b=2", "no colon"}
+	expected := map[string][]string{
+		"Content-Type": {"application/json"},
+		"Set-Cookie":   {"a=1", "b=2"},
+	}
+
+	if result := getHeadersAsMap(headers); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected: %v, Got: %v", expected, result)
+	}
+}
+
+func Test_getRequestHeaders(t *testing.T) {
+	tests := map[string]struct {
+		headers         []string
+		auth            *data.Auth
+		expectedHeaders []string
+	}{
+		"Basic auth": {
+			headers:         []string{"Accept: */*"},
+			auth:            &data.Auth{Scheme: data.AuthBasic, Username: "user", Password: "secret"},
+			expectedHeaders: []string{"Accept: */*", "Authorization: Basic ********"},
+		},
+		"Digest auth": {
+			auth:            &data.Auth{Scheme: data.AuthDigest, Username: "user", Password: "secret"},
+			expectedHeaders: []string{"Authorization: Digest ********"},
+		},
+		"Signed header": {
+			headers:         []string{"authorization: AWS4-HMAC-SHA256 Credential=AKID/20150830, Signature=abc", "X-Amz-Date: 20150830T123600Z"},
+			expectedHeaders: []string{"authorization: AWS4-HMAC-SHA256 ********", "X-Amz-Date: 20150830T123600Z"},
+		},
+		"Session token": {
+			headers:         []string{"X-Amz-Security-Token: FQoGZXIvYXdzE", "X-Amz-Date: 20150830T123600Z"},
+			expectedHeaders: []string{"X-Amz-Security-Token: ********", "X-Amz-Date: 20150830T123600Z"},
+		},
+	}
+
+	for name, test := range tests {
+		backendInput := &data.BackendInput{Headers: test.headers, Auth: test.auth}
+
+		if requestHeaders := getRequestHeaders(backendInput); !reflect.DeepEqual(requestHeaders, test.expectedHeaders) {
+			t.Errorf("Test: %s, Expected: %v, Got: %v", name, test.expectedHeaders, requestHeaders)
+		}
+	}
+}
+
+func Test_getBodyAndEncoding(t *testing.T) {
+	tests := map[string]struct {
+		body             string
+		headers          []string
+		expectedBody     interface{}
+		expectedEncoding string
+	}{
+		"JSON body": {
+			body:             `{"a": 1}`,
+			headers:          []string{"Content-Type: application/json; charset=utf-8"},
+			expectedBody:     json.RawMessage(`{"a": 1}`),
+			expectedEncoding: bodyEncodingJSON,
+		},
+		"JSON suffix": {
+			body:             `[1, 2]`,
+			headers:          []string{"Content-Type: application/problem+json"},
+			expectedBody:     json.RawMessage(`[1, 2]`),
+			expectedEncoding: bodyEncodingJSON,
+		},
+		"Invalid JSON": {
+			body:             `{"a":`,
+			headers:          []string{"Content-Type: application/json"},
+			expectedBody:     `{"a":`,
+			expectedEncoding: bodyEncodingText,
+		},
+		"JSON without content type": {
+			body:             `{"a": 1}`,
+			expectedBody:     `{"a": 1}`,
+			expectedEncoding: bodyEncodingText,
+		},
+		"Binary": {
+			body:             "\xff\xfe",
+			headers:          []string{"Content-Type: application/octet-stream"},
+			expectedBody:     "//4=",
+			expectedEncoding: bodyEncodingBase64,
+		},
+	}
+
+	for name, test := range tests {
+		body, encoding := getBodyAndEncoding(test.body, test.headers)
+		if !reflect.DeepEqual(body, test.expectedBody) || encoding != test.expectedEncoding {
+			t.Errorf("Test: %s, Expected: %v %s, Got: %v %s", name, test.expectedBody, test.expectedEncoding, body, encoding)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package call
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+	"github.com/pkg/errors"
+)
+
+// printer prints the request as something to run elsewhere,
+// a backend prints its command line and the code printers
+// a snippet in their language
+type printer interface {
+	getAsString() string
+}
+
+var ValidPrinters = map[string]func(*data.BackendInput) printer{
+	"go":         newGoPrinter,
+	"python":     newPythonPrinter,
+	"javascript": newJavascriptPrinter,
+}
+
+// PrinterNames returns the code printers sorted by name
+func PrinterNames() []string {
+	printerNames := []string{}
+	for printerName := range ValidPrinters {
+		printerNames = append(printerNames, printerName)
+	}
+
+	sort.Strings(printerNames)
+
+	return printerNames
+}
+
+func ValidPrinter(printerName string) bool {
+	_, exists := ValidPrinters[printerName]
+	return exists
+}
+
+// PrintAs returns the request as code in the language. The body
+// is embedded in the code so one piped to ain is read first.
+func PrintAs(backendInput *data.BackendInput, printerName string) (string, error) {
+	newPrinter, exists := ValidPrinters[printerName]
+	if !exists {
+		return "", errors.Errorf("unknown language: %s, valid languages are: %s", printerName, strings.Join(PrinterNames(), ", "))
+	}
+
+	if backendInput.BodyFromStdin {
+		if err := backendInput.ReadBodyFromStdin(); err != nil {
+			return "", err
+		}
+	}
+
+	return newPrinter(backendInput).getAsString(), nil
+}
+
+type printerHeader struct {
+	name  string
+	value string
+}
+
+// getPrinterHeaders returns the headers with the same name joined
+// (as a map in the languages can only hold one of each), in the
+// order they first appear
+func getPrinterHeaders(headers []string) []printerHeader {
+	printerHeaders := []printerHeader{}
+	headerIndexes := map[string]int{}
+
+	for _, header := range headers {
+		headerParts := strings.SplitN(header, ":", 2)
+		if len(headerParts) != 2 {
+			continue
+		}
+
+		name, value := strings.TrimSpace(headerParts[0]), strings.TrimSpace(headerParts[1])
+		if headerIndex, found := headerIndexes[strings.ToLower(name)]; found {
+			separator := ", "
+			if strings.EqualFold(name, "Cookie") {
+				separator = "; "
+			}
+
+			printerHeaders[headerIndex].value += separator + value
+			continue
+		}
+
+		headerIndexes[strings.ToLower(name)] = len(printerHeaders)
+		printerHeaders = append(printerHeaders, printerHeader{name: name, value: value})
+	}
+
+	return printerHeaders
+}
+
+// quoteString quotes the text as a double quoted string,
+// which is the same for json, python and javascript
+func quoteString(text string) string {
+	var quoted bytes.Buffer
+
+	encoder := json.NewEncoder(&quoted)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(text)
+
+	return strings.TrimSuffix(quoted.String(), "\n")
+}
+
+// quoteBodyLines quotes each body line with its newline so a
+// body is printed over the same number of lines as in the template
+func quoteBodyLines(body string, quote func(string) string) []string {
+	quotedLines := []string{}
+
+	bodyLines := strings.SplitAfter(body, "\n")
+	for _, bodyLine := range bodyLines {
+		if bodyLine != "" {
+			quotedLines = append(quotedLines, quote(bodyLine))
+		}
+	}
+
+	return quotedLines
+}
+
+// getUntranslated returns what the code cannot do for each
+// [Config] not in translatedConfig and any [BackendOptions]
+func getUntranslated(backendInput *data.BackendInput, translatedConfig map[string]bool) []string {
+	config := backendInput.Config
+	untranslated := []string{}
+
+	for _, setConfig := range []struct {
+		name  string
+		isSet bool
+	}{
+		{"Timeout", config.Timeout != data.TimeoutNotSet},
+		{"CookieJar", config.CookieJar != nil},
+		{"Proxy", config.Proxy != nil},
+		{"CACert", config.CACert != nil},
+		{"ClientCert", config.ClientCert != nil},
+		{"ClientKey", config.ClientKey != nil},
+		{"Insecure", data.IsTrue(config.Insecure)},
+		{"FollowRedirects", config.FollowRedirects != nil},
+		{"MaxRedirects", config.MaxRedirects != nil},
+		{"Verbose", data.IsTrue(config.Verbose)},
+		{"Compressed", data.IsTrue(config.Compressed)},
+		{"HTTPVersion", config.HTTPVersion != nil},
+		{"ConnectTimeout", config.ConnectTimeout != nil},
+		{"UnixSocket", config.UnixSocket != nil},
+		{"Retries", config.Retries != nil},
+	} {
+		if setConfig.isSet && !translatedConfig[setConfig.name] {
+			untranslated = append(untranslated, "[Config] "+setConfig.name)
+		}
+	}
+
+	if len(backendInput.BackendOptions) > 0 {
+		untranslated = append(untranslated, "[BackendOptions]")
+	}
+
+	return untranslated
+}
+
+func writeUntranslated(builder *strings.Builder, commentPrefix string, untranslated []string) {
+	if len(untranslated) == 0 {
+		return
+	}
+
+	builder.WriteString(fmt.Sprintf("%s Not translated: %s\n\n", commentPrefix, strings.Join(untranslated, ", ")))
+}
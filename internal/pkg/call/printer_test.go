@@ -0,0 +1,103 @@
+package call
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+)
+
+func Test_getPrinterHeaders(t *testing.T) {
+	tests := map[string]struct {
+		headers  []string
+		expected []printerHeader
+	}{
+		"Order kept": {
+			headers:  []string{"B: 2", "A:1"},
+			expected: []printerHeader{{name: "B", value: "2"}, {name: "A", value: "1"}},
+		},
+		"Same name joined": {
+			headers:  []string{"Accept: text/html", "Cookie: a=1", "accept: */*", "Cookie: b=2"},
+			expected: []printerHeader{{name: "Accept", value: "text/html, */*"}, {name: "Cookie", value: "a=1; b=2"}},
+		},
+		"No colon skipped": {
+			headers:  []string{"nope"},
+			expected: []printerHeader{},
+		},
+	}
+
+	for name, test := range tests {
+		if got := getPrinterHeaders(test.headers); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("Test: %s, Expected: %v, Got: %v", name, test.expected, got)
+		}
+	}
+}
+
+func Test_quoteString(t *testing.T) {
+	tests := map[string]struct {
+		text     string
+		expected string
+	}{
+		"Quotes and backslashes": {text: `say "hi" \o/`, expected: `"say \"hi\" \\o/"`},
+		"Control characters":     {text: "a\tb\n", expected: `"a\tb\n"`},
+		"Not html escaped":       {text: "a=1&b=<2>", expected: `"a=1&b=<2>"`},
+	}
+
+	for name, test := range tests {
+		if got := quoteString(test.text); got != test.expected {
+			t.Errorf("Test: %s, Expected: %s, Got: %s", name, test.expected, got)
+		}
+	}
+}
+
+func Test_quoteGoBody(t *testing.T) {
+	tests := map[string]struct {
+		body     string
+		expected string
+	}{
+		"Raw string": {body: "{\n  \"a\": 1\n}", expected: "`{\n  \"a\": 1\n}`"},
+		"Backtick":   {body: "a`b\nc", expected: "\"a`b\\n\" +\n\t\t\"c\""},
+	}
+
+	for name, test := range tests {
+		if got := quoteGoBody(test.body); got != test.expected {
+			t.Errorf("Test: %s, Expected: %s, Got: %s", name, test.expected, got)
+		}
+	}
+}
+
+func Test_goPrinter(t *testing.T) {
+	hostUrl, _ := url.Parse("http://localhost:8080/users")
+	followRedirects := false
+
+	got := newGoPrinter(&data.BackendInput{
+		Host:    hostUrl,
+		Headers: []string{"Accept: application/json", "Host: api.example.com"},
+		Auth:    &data.Auth{Scheme: data.AuthBasic, Username: "user", Password: "s3cr3t"},
+		Config:  data.Config{Timeout: 5, FollowRedirects: &followRedirects},
+	}).getAsString()
+
+	for _, expected := range []string{
+		"\t\"time\"\n",
+		`req, err := http.NewRequest("GET", "http://localhost:8080/users", nil)`,
+		`req.Header.Set("Accept", "application/json")`,
+		`req.Host = "api.example.com"`,
+		`req.SetBasicAuth("user", "********")`,
+		"Timeout: 5 * time.Second,",
+		"return http.ErrUseLastResponse",
+	} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("Test: Go printer, Expected: %s, Got: %s", expected, got)
+		}
+	}
+
+	if strings.Contains(got, `req.Header.Set("Host"`) {
+		t.Errorf("Test: Go printer, Expected: no Host header set, Got: %s", got)
+	}
+
+	if strings.Contains(got, `"strings"`) {
+		t.Errorf("Test: Go printer, Expected: no strings import without a body, Got: %s", got)
+	}
+}
@@ -0,0 +1,115 @@
+package call
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+)
+
+type pythonPrinter struct {
+	backendInput *data.BackendInput
+}
+
+func newPythonPrinter(backendInput *data.BackendInput) printer {
+	return &pythonPrinter{backendInput: backendInput}
+}
+
+var pythonTranslatedConfig = map[string]bool{"Timeout": true, "Insecure": true, "FollowRedirects": true, "Proxy": true}
+
+func pythonBool(value bool) string {
+	if value {
+		return "True"
+	}
+
+	return "False"
+}
+
+func (p *pythonPrinter) getHeaders(auth *data.Auth) []printerHeader {
+	headers := getPrinterHeaders(getHeaders(p.backendInput, true))
+	if auth != nil && auth.Scheme == data.AuthBearer {
+		headers = append(headers, printerHeader{name: "Authorization", value: "Bearer " + auth.Token})
+	}
+
+	return headers
+}
+
+func (p *pythonPrinter) getRequestArguments(auth *data.Auth, hasHeaders bool) ([]string, []string) {
+	config := p.backendInput.Config
+	imports := []string{"import requests"}
+	arguments := []string{quoteString(p.backendInput.GetMethod()), "url"}
+
+	if hasHeaders {
+		arguments = append(arguments, "headers=headers")
+	}
+
+	if len(p.backendInput.Body) > 0 {
+		// A str body is sent as latin-1
+		arguments = append(arguments, "data=data.encode()")
+	}
+
+	if auth != nil {
+		switch auth.Scheme {
+		case data.AuthBasic:
+			arguments = append(arguments, "auth=("+quoteString(auth.Username)+", "+quoteString(auth.Password)+")")
+		case data.AuthDigest:
+			imports = append(imports, "from requests.auth import HTTPDigestAuth")
+			arguments = append(arguments, "auth=HTTPDigestAuth("+quoteString(auth.Username)+", "+quoteString(auth.Password)+")")
+		}
+	}
+
+	if config.Timeout != data.TimeoutNotSet {
+		arguments = append(arguments, "timeout="+strconv.Itoa(int(config.Timeout)))
+	}
+
+	if data.IsTrue(config.Insecure) {
+		arguments = append(arguments, "verify=False")
+	}
+
+	if config.FollowRedirects != nil {
+		arguments = append(arguments, "allow_redirects="+pythonBool(*config.FollowRedirects))
+	}
+
+	if config.Proxy != nil {
+		proxy := quoteString(*config.Proxy)
+		arguments = append(arguments, "proxies={\"http\": "+proxy+", \"https\": "+proxy+"}")
+	}
+
+	return imports, arguments
+}
+
+func (p *pythonPrinter) getAsString() string {
+	var builder strings.Builder
+
+	writeUntranslated(&builder, "#", getUntranslated(p.backendInput, pythonTranslatedConfig))
+
+	auth := getAuth(p.backendInput, true)
+	headers := p.getHeaders(auth)
+	imports, arguments := p.getRequestArguments(auth, len(headers) > 0)
+
+	builder.WriteString(strings.Join(imports, "\n") + "\n\n")
+	builder.WriteString("url = " + quoteString(p.backendInput.Host.String()) + "\n")
+
+	if len(headers) > 0 {
+		builder.WriteString("headers = {\n")
+		for _, header := range headers {
+			builder.WriteString("    " + quoteString(header.name) + ": " + quoteString(header.value) + ",\n")
+		}
+
+		builder.WriteString("}\n")
+	}
+
+	if len(p.backendInput.Body) > 0 {
+		builder.WriteString("data = (\n")
+		for _, quotedLine := range quoteBodyLines(p.backendInput.GetBody(), quoteString) {
+			builder.WriteString("    " + quotedLine + "\n")
+		}
+
+		builder.WriteString(")\n")
+	}
+
+	builder.WriteString("\nresponse = requests.request(" + strings.Join(arguments, ", ") + ")\n")
+	builder.WriteString("print(response.text, end=\"\")\n")
+
+	return builder.String()
+}
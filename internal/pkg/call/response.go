@@ -0,0 +1,91 @@
+package call
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+)
+
+const responseHeadPrefix = "HTTP/"
+
+// splitResponseHead splits off the response head(s) the backend
+// printed before the body. When there are several heads (redirects,
+// 100 continue or a proxy connect) the last one is the response.
+func splitResponseHead(stdout string) (string, string, bool) {
+	head := ""
+	found := false
+
+	for strings.HasPrefix(stdout, responseHeadPrefix) {
+		headEnd, separatorLen := strings.Index(stdout, "\r\n\r\n"), 4
+		if lfHeadEnd := strings.Index(stdout, "\n\n"); lfHeadEnd >= 0 && (headEnd < 0 || lfHeadEnd < headEnd) {
+			headEnd, separatorLen = lfHeadEnd, 2
+		}
+
+		if headEnd < 0 {
+			// Only a head and no body
+			return strings.TrimRight(stdout, "\r\n"), "", true
+		}
+
+		head = stdout[:headEnd]
+		stdout = stdout[headEnd+separatorLen:]
+		found = true
+	}
+
+	return head, stdout, found
+}
+
+// parseResponseHead returns the status code, the status
+// (e g 404 Not Found) and the header lines in the head
+func parseResponseHead(head string) (int, string, []string) {
+	lines := strings.Split(strings.ReplaceAll(head, "\r\n", "\n"), "\n")
+
+	// HTTP/1.1 404 Not Found
+	statusLineParts := strings.SplitN(lines[0], " ", 2)
+	if len(statusLineParts) != 2 {
+		return 0, "", nil
+	}
+
+	status := strings.TrimSpace(statusLineParts[1])
+
+	statusCode, err := strconv.Atoi(strings.SplitN(status, " ", 2)[0])
+	if err != nil {
+		return 0, "", nil
+	}
+
+	headers := []string{}
+	for _, line := range lines[1:] {
+		if line = strings.TrimSpace(line); line != "" {
+			headers = append(headers, line)
+		}
+	}
+
+	return statusCode, status, headers
+}
+
+// inspectResponse reads the status and headers from the response
+// head in stdout and removes the head unless the user asked for it
+func inspectResponse(backendOutput *data.BackendOutput, keepHead bool) {
+	head, body, found := splitResponseHead(backendOutput.Stdout)
+	if !found {
+		return
+	}
+
+	backendOutput.StatusCode, backendOutput.Status, backendOutput.Headers = parseResponseHead(head)
+
+	if !keepHead {
+		backendOutput.Stdout = body
+	}
+}
+
+// GetHeader returns the first value of the named response header
+func GetHeader(headers []string, name string) (string, bool) {
+	for _, header := range headers {
+		headerParts := strings.SplitN(header, ":", 2)
+		if len(headerParts) == 2 && strings.EqualFold(strings.TrimSpace(headerParts[0]), name) {
+			return strings.TrimSpace(headerParts[1]), true
+		}
+	}
+
+	return "", false
+}
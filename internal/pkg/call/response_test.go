@@ -0,0 +1,87 @@
+package call
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+)
+
+func Test_inspectResponse(t *testing.T) {
+	tests := map[string]struct {
+		stdout             string
+		keepHead           bool
+		expectedStdout     string
+		expectedStatusCode int
+		expectedStatus     string
+		expectedHeaders    []string
+	}{
+		"Head and body": {
+			stdout:             "HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\n\r\nbody\n",
+			expectedStdout:     "body\n",
+			expectedStatusCode: 200,
+			expectedStatus:     "200 OK",
+			expectedHeaders:    []string{"Content-Type: text/plain"},
+		},
+		"Head kept": {
+			stdout:             "HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\n\r\nbody\n",
+			keepHead:           true,
+			expectedStdout:     "HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\n\r\nbody\n",
+			expectedStatusCode: 200,
+			expectedStatus:     "200 OK",
+			expectedHeaders:    []string{"Content-Type: text/plain"},
+		},
+		"Last head after redirect": {
+			stdout:             "HTTP/1.1 302 Found\r\nLocation: /end\r\n\r\nHTTP/2 404\r\nServer: goat\r\n\r\nnot found",
+			expectedStdout:     "not found",
+			expectedStatusCode: 404,
+			expectedStatus:     "404",
+			expectedHeaders:    []string{"Server: goat"},
+		},
+		"Newline separated head": {
+			stdout:             "HTTP/1.1 503 Service Unavailable\nRetry-After: 2\n\n",
+			expectedStdout:     "",
+			expectedStatusCode: 503,
+			expectedStatus:     "503 Service Unavailable",
+			expectedHeaders:    []string{"Retry-After: 2"},
+		},
+		"Only head": {
+			stdout:             "HTTP/1.1 204 No Content\r\nServer: goat\r\n",
+			expectedStdout:     "",
+			expectedStatusCode: 204,
+			expectedStatus:     "204 No Content",
+			expectedHeaders:    []string{"Server: goat"},
+		},
+		"No head": {
+			stdout:         "HTTP is not a head",
+			expectedStdout: "HTTP is not a head",
+		},
+	}
+
+	for name, test := range tests {
+		backendOutput := &data.BackendOutput{Stdout: test.stdout}
+		inspectResponse(backendOutput, test.keepHead)
+
+		if backendOutput.Stdout != test.expectedStdout ||
+			backendOutput.StatusCode != test.expectedStatusCode ||
+			backendOutput.Status != test.expectedStatus ||
+			!reflect.DeepEqual(backendOutput.Headers, test.expectedHeaders) {
+			t.Errorf("Test: %s, Expected: %q %d %q %v, Got: %q %d %q %v", name,
+				test.expectedStdout, test.expectedStatusCode, test.expectedStatus, test.expectedHeaders,
+				backendOutput.Stdout, backendOutput.StatusCode, backendOutput.Status, backendOutput.Headers)
+		}
+	}
+}
+
+func Test_GetHeader(t *testing.T) {
+	headers := []string{"Content-Type: text/plain", "retry-after: 5", "Retry-After: 10"}
+
+	value, found := GetHeader(headers, "Retry-After")
+	if !found || value != "5" {
+		t.Errorf("Expected: 5, Got: %s (found %v)", value, found)
+	}
+
+	if _, found := GetHeader(headers, "Location"); found {
+		t.Errorf("Expected Location not to be found")
+	}
+}
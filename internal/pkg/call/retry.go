@@ -0,0 +1,104 @@
+package call
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+)
+
+var defaultRetryStatus = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+const defaultRetryBackoff = 1
+
+// Neither the doubled backoff nor a Retry-After waits longer
+const maxRetryDelay = 10 * time.Minute
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getRetries(config data.Config) int {
+	if config.Retries == nil {
+		return 0
+	}
+
+	return int(*config.Retries)
+}
+
+// getRetryReason returns why the call should be retried. Without any
+// RetryExitCodes a backend exiting without a response is retried.
+func getRetryReason(config data.Config, backendOutput *data.BackendOutput) (string, bool) {
+	retryStatus := config.RetryStatus
+	if retryStatus == nil {
+		retryStatus = defaultRetryStatus
+	}
+
+	if backendOutput.StatusCode != 0 && containsInt(retryStatus, backendOutput.StatusCode) {
+		return "got " + backendOutput.Status, true
+	}
+
+	exitCodeReason := fmt.Sprintf("exited with %d", backendOutput.ExitCode)
+
+	if config.RetryExitCodes != nil {
+		return exitCodeReason, containsInt(config.RetryExitCodes, backendOutput.ExitCode)
+	}
+
+	return exitCodeReason + " and no response", backendOutput.ExitCode > 0 && backendOutput.StatusCode == 0
+}
+
+// getRetryDelay doubles the backoff for each attempt unless
+// the server tells when to come back in a Retry-After header
+func getRetryDelay(config data.Config, attempt int, headers []string, now time.Time) time.Duration {
+	if retryAfter, found := GetHeader(headers, "Retry-After"); found {
+		if retryAfterSeconds, err := strconv.ParseInt(retryAfter, 10, 64); err == nil && retryAfterSeconds >= 0 {
+			if retryAfterSeconds > int64(maxRetryDelay/time.Second) {
+				return maxRetryDelay
+			}
+
+			return time.Duration(retryAfterSeconds) * time.Second
+		}
+
+		if retryAfterTime, err := http.ParseTime(retryAfter); err == nil {
+			if retryAfterTime.Before(now) {
+				return 0
+			}
+
+			if retryDelay := retryAfterTime.Sub(now).Truncate(time.Second); retryDelay < maxRetryDelay {
+				return retryDelay
+			}
+
+			return maxRetryDelay
+		}
+	}
+
+	retryBackoff := int32(defaultRetryBackoff)
+	if config.RetryBackoff != nil {
+		retryBackoff = *config.RetryBackoff
+	}
+
+	// Doubled one attempt at a time so a large attempt can't overflow
+	retryDelay := time.Duration(retryBackoff) * time.Second
+	for i := 0; i < attempt && retryDelay < maxRetryDelay; i++ {
+		retryDelay *= 2
+	}
+
+	if retryDelay > maxRetryDelay {
+		return maxRetryDelay
+	}
+
+	return retryDelay
+}
@@ -0,0 +1,129 @@
+package call
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+)
+
+func Test_getRetryReason(t *testing.T) {
+	tests := map[string]struct {
+		config         data.Config
+		backendOutput  data.BackendOutput
+		expectedReason string
+		expectedRetry  bool
+	}{
+		"Default retry status": {
+			backendOutput:  data.BackendOutput{StatusCode: 503, Status: "503 Service Unavailable", ExitCode: 0},
+			expectedReason: "got 503 Service Unavailable",
+			expectedRetry:  true,
+		},
+		"Status not retried": {
+			backendOutput:  data.BackendOutput{StatusCode: 404, Status: "404 Not Found", ExitCode: 8},
+			expectedReason: "exited with 8 and no response",
+			expectedRetry:  false,
+		},
+		"Configured retry status": {
+			config:         data.Config{RetryStatus: []int{404}},
+			backendOutput:  data.BackendOutput{StatusCode: 404, Status: "404 Not Found"},
+			expectedReason: "got 404 Not Found",
+			expectedRetry:  true,
+		},
+		"No response": {
+			backendOutput:  data.BackendOutput{ExitCode: 7},
+			expectedReason: "exited with 7 and no response",
+			expectedRetry:  true,
+		},
+		"Configured exit code": {
+			config:         data.Config{RetryExitCodes: []int{28}},
+			backendOutput:  data.BackendOutput{ExitCode: 28},
+			expectedReason: "exited with 28",
+			expectedRetry:  true,
+		},
+		"Exit code not configured": {
+			config:         data.Config{RetryExitCodes: []int{28}},
+			backendOutput:  data.BackendOutput{ExitCode: 7},
+			expectedReason: "exited with 7",
+			expectedRetry:  false,
+		},
+		"Success": {
+			backendOutput:  data.BackendOutput{StatusCode: 200, Status: "200 OK"},
+			expectedReason: "exited with 0 and no response",
+			expectedRetry:  false,
+		},
+	}
+
+	for name, test := range tests {
+		reason, retry := getRetryReason(test.config, &test.backendOutput)
+		if retry != test.expectedRetry || (retry && reason != test.expectedReason) {
+			t.Errorf("Test: %s, Expected: %v %s, Got: %v %s", name, test.expectedRetry, test.expectedReason, retry, reason)
+		}
+	}
+}
+
+func Test_getRetryDelay(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	three := int32(3)
+	maxBackoff := int32(math.MaxInt32)
+
+	tests := map[string]struct {
+		config        data.Config
+		attempt       int
+		headers       []string
+		expectedDelay time.Duration
+	}{
+		"Default backoff": {
+			attempt:       0,
+			expectedDelay: time.Second,
+		},
+		"Doubled backoff": {
+			config:        data.Config{RetryBackoff: &three},
+			attempt:       2,
+			expectedDelay: 12 * time.Second,
+		},
+		"Retry-After seconds": {
+			attempt:       2,
+			headers:       []string{"Retry-After: 5"},
+			expectedDelay: 5 * time.Second,
+		},
+		"Retry-After date": {
+			headers:       []string{"Retry-After: Mon, 19 Oct 2026 12:00:30 GMT"},
+			expectedDelay: 30 * time.Second,
+		},
+		"Retry-After date passed": {
+			headers:       []string{"Retry-After: Mon, 19 Oct 2026 11:00:00 GMT"},
+			expectedDelay: 0,
+		},
+		"Large attempt": {
+			attempt:       200,
+			expectedDelay: maxRetryDelay,
+		},
+		"Large backoff": {
+			config:        data.Config{RetryBackoff: &maxBackoff},
+			attempt:       3,
+			expectedDelay: maxRetryDelay,
+		},
+		"Huge Retry-After seconds": {
+			headers:       []string{"Retry-After: 99999999999999999"},
+			expectedDelay: maxRetryDelay,
+		},
+		"Retry-After date far ahead": {
+			headers:       []string{"Retry-After: Fri, 19 Oct 2126 12:00:00 GMT"},
+			expectedDelay: maxRetryDelay,
+		},
+		"Retry-After invalid": {
+			config:        data.Config{RetryBackoff: &three},
+			headers:       []string{"Retry-After: soon"},
+			expectedDelay: 3 * time.Second,
+		},
+	}
+
+	for name, test := range tests {
+		delay := getRetryDelay(test.config, test.attempt, test.headers, now)
+		if delay != test.expectedDelay {
+			t.Errorf("Test: %s, Expected: %s, Got: %s", name, test.expectedDelay, delay)
+		}
+	}
+}
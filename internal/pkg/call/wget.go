@@ -2,8 +2,11 @@ package call
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"os/exec"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/jonaslu/ain/internal/pkg/data"
@@ -13,6 +16,7 @@ import (
 type wget struct {
 	backendInput *data.BackendInput
 	binaryName   string
+	shell        utils.Shell
 }
 
 var outputToStdoutRegexp = regexp.MustCompile(`-\w*O\s*-`)
@@ -38,14 +42,15 @@ func newWgetBackend(backendInput *data.BackendInput, binaryName string) backend
 	return &wget{
 		backendInput: backendInput,
 		binaryName:   binaryName,
+		shell:        utils.GetShell(backendInput.PrintShell),
 	}
 }
 
 func (wget *wget) getHeaderArguments(escape bool) []string {
 	args := []string{}
-	for _, header := range wget.backendInput.Headers {
+	for _, header := range getHeaders(wget.backendInput, escape) {
 		if escape {
-			args = append(args, "--header="+utils.EscapeForShell(header))
+			args = append(args, "--header="+wget.shell.Escape(header))
 		} else {
 			args = append(args, "--header="+header)
 		}
@@ -59,7 +64,7 @@ func (wget *wget) getMethodArgument(escape bool) string {
 		methodCapitalized := strings.ToUpper(wget.backendInput.Method)
 
 		if escape {
-			return "--method=" + utils.EscapeForShell(methodCapitalized)
+			return "--method=" + wget.shell.Escape(methodCapitalized)
 		}
 
 		return "--method=" + methodCapitalized
@@ -68,6 +73,163 @@ func (wget *wget) getMethodArgument(escape bool) string {
 	return ""
 }
 
+func (wget *wget) getAuthArguments(escape bool) []string {
+	auth := getAuth(wget.backendInput, escape)
+	if auth == nil {
+		return []string{}
+	}
+
+	username := auth.Username
+	password := auth.Password
+	bearerHeader := auth.BearerHeader()
+	if escape {
+		username = wget.shell.Escape(username)
+		password = wget.shell.Escape(password)
+		bearerHeader = wget.shell.Escape(bearerHeader)
+	}
+
+	userAndPasswordArgs := []string{"--user=" + username, "--password=" + password}
+
+	switch auth.Scheme {
+	case data.AuthBasic:
+		// Wget waits for a challenge before sending basic auth
+		return append([]string{"--auth-no-challenge"}, userAndPasswordArgs...)
+	case data.AuthDigest:
+		return userAndPasswordArgs
+	case data.AuthBearer:
+		return []string{"--header=" + bearerHeader}
+	}
+
+	return []string{}
+}
+
+func (wget *wget) getCookieJarArguments(escape bool) []string {
+	cookieJar := wget.backendInput.Config.CookieJar
+	if cookieJar == nil {
+		return []string{}
+	}
+
+	cookieJarFilename := *cookieJar
+	if escape {
+		cookieJarFilename = wget.shell.Escape(cookieJarFilename)
+	}
+
+	args := []string{}
+
+	// Wget errors on loading a cookie file that does not exist
+	if _, err := os.Stat(*cookieJar); err == nil {
+		args = append(args, "--load-cookies="+cookieJarFilename)
+	}
+
+	// Session cookies are what logins usually set
+	return append(args, "--save-cookies="+cookieJarFilename, "--keep-session-cookies")
+}
+
+func wgetUnsupportedConfig(config data.Config) []string {
+	unsupportedConfig := []string{}
+
+	if config.Proxy != nil && !strings.HasPrefix(strings.ToLower(*config.Proxy), "http") {
+		unsupportedConfig = append(unsupportedConfig, fmt.Sprintf("Backend wget only supports http(s) proxies, got Proxy=%s", *config.Proxy))
+	}
+
+	if config.HTTPVersion != nil && *config.HTTPVersion != "1.1" {
+		unsupportedConfig = append(unsupportedConfig, fmt.Sprintf("Backend wget only supports HTTPVersion=1.1, got HTTPVersion=%s", *config.HTTPVersion))
+	}
+
+	if config.UnixSocket != nil {
+		unsupportedConfig = append(unsupportedConfig, "Backend wget cannot call over a unix socket, use curl or httpie")
+	}
+
+	return unsupportedConfig
+}
+
+func (wget *wget) getConfigArguments(escape bool) []string {
+	config := wget.backendInput.Config
+	args := []string{}
+
+	if config.Proxy != nil {
+		// Wget only reads proxies from the environment or .wgetrc
+		proxy := *config.Proxy
+		if escape {
+			proxy = wget.shell.Escape(proxy)
+		}
+
+		args = append(args, "-e", "use_proxy=on", "-e", "http_proxy="+proxy, "-e", "https_proxy="+proxy)
+	}
+
+	if config.CACert != nil {
+		caCert := *config.CACert
+		if escape {
+			caCert = wget.shell.Escape(caCert)
+		}
+
+		args = append(args, "--ca-certificate="+caCert)
+	}
+
+	if config.ClientCert != nil {
+		clientCert := *config.ClientCert
+		if escape {
+			clientCert = wget.shell.Escape(clientCert)
+		}
+
+		args = append(args, "--certificate="+clientCert)
+	}
+
+	if config.ClientKey != nil {
+		clientKey := *config.ClientKey
+		if escape {
+			clientKey = wget.shell.Escape(clientKey)
+		}
+
+		args = append(args, "--private-key="+clientKey)
+	}
+
+	if data.IsTrue(config.Insecure) {
+		args = append(args, "--no-check-certificate")
+	}
+
+	// Wget follows up to 20 redirects unless told otherwise
+	if config.MaxRedirects != nil && config.FollowsRedirects() {
+		args = append(args, "--max-redirect="+strconv.Itoa(int(*config.MaxRedirects)))
+	} else if config.FollowRedirects != nil && !*config.FollowRedirects {
+		args = append(args, "--max-redirect=0")
+	}
+
+	if data.IsTrue(config.Verbose) {
+		args = append(args, "--server-response")
+	}
+
+	if data.IsTrue(config.Compressed) {
+		args = append(args, "--compression=auto")
+	}
+
+	if config.ConnectTimeout != nil {
+		args = append(args, "--connect-timeout="+strconv.Itoa(int(*config.ConnectTimeout)))
+	}
+
+	return args
+}
+
+func (wget *wget) printsResponseHead() bool {
+	return hasBackendOption(wget.backendInput.BackendOptions, func(backendOption string) bool {
+		return backendOption == "--save-headers"
+	})
+}
+
+func (wget *wget) getInspectResponseArguments() []string {
+	if !wget.backendInput.InspectResponse {
+		return []string{}
+	}
+
+	// Wget only outputs the response on 2xx without --content-on-error
+	args := []string{"--content-on-error"}
+	if !wget.printsResponseHead() {
+		args = append(args, "--save-headers")
+	}
+
+	return args
+}
+
 func (wget *wget) getBodyArgument() []string {
 	if wget.backendInput.TempFileName != "" {
 		return []string{"--body-file=" + wget.backendInput.TempFileName}
@@ -76,6 +238,14 @@ func (wget *wget) getBodyArgument() []string {
 	return []string{}
 }
 
+func (wget *wget) getPrintedBodyArgument() []string {
+	if !wget.backendInput.PrintsInlineBody() {
+		return wget.getBodyArgument()
+	}
+
+	return []string{"--body-data=" + getInlineBody(wget.backendInput, wget.shell)}
+}
+
 func (wget *wget) getAsCmd(ctx context.Context) *exec.Cmd {
 	args := []string{}
 	for _, backendOpt := range wget.backendInput.BackendOptions {
@@ -87,6 +257,10 @@ func (wget *wget) getAsCmd(ctx context.Context) *exec.Cmd {
 	}
 
 	args = append(args, wget.getHeaderArguments(false)...)
+	args = append(args, wget.getAuthArguments(false)...)
+	args = append(args, wget.getCookieJarArguments(false)...)
+	args = append(args, wget.getConfigArguments(false)...)
+	args = append(args, wget.getInspectResponseArguments()...)
 	args = append(args, wget.getBodyArgument()...)
 
 	args = append(args, wget.backendInput.Host.String())
@@ -101,7 +275,7 @@ func (wget *wget) getAsString() string {
 	for _, optionLine := range wget.backendInput.BackendOptions {
 		lineArguments := []string{}
 		for _, option := range optionLine {
-			lineArguments = append(lineArguments, utils.EscapeForShell(option))
+			lineArguments = append(lineArguments, wget.shell.Escape(option))
 		}
 		args = append(args, lineArguments)
 	}
@@ -114,13 +288,17 @@ func (wget *wget) getAsString() string {
 		args = append(args, []string{header})
 	}
 
-	args = append(args, wget.getBodyArgument())
+	args = append(args, wget.getAuthArguments(true))
+	args = append(args, wget.getCookieJarArguments(true))
+	args = append(args, wget.getConfigArguments(true))
+
+	args = append(args, wget.getPrintedBodyArgument())
 
 	args = append(args, []string{
-		utils.EscapeForShell(wget.backendInput.Host.String()),
+		wget.shell.Escape(wget.backendInput.Host.String()),
 	})
 
-	output := wget.binaryName + " " + utils.PrettyPrintStringsForShell(args)
+	output := wget.shell.CommandName(wget.binaryName) + " " + wget.shell.PrettyPrint(args)
 
 	return output
 }
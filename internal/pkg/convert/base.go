@@ -0,0 +1,176 @@
+package convert
+
+import (
+	"net/textproto"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// Headers describing the body belong with the body
+var bodyHeaders = []string{"Content-Type", "Content-Length"}
+
+func isBodyHeader(header string) bool {
+	headerName := textproto.CanonicalMIMEHeaderKey(strings.TrimSpace(strings.SplitN(header, ":", 2)[0]))
+	for _, bodyHeader := range bodyHeaders {
+		if headerName == bodyHeader {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitOrigin splits a url into the scheme and
+// host (the origin) and the rest of the url
+func splitOrigin(host string) (string, string) {
+	hostUrl, err := url.Parse(host)
+	if err != nil || hostUrl.Scheme == "" || hostUrl.Host == "" {
+		return "", host
+	}
+
+	origin := hostUrl.Scheme + "://" + hostUrl.Host
+	return origin, strings.TrimPrefix(host, origin)
+}
+
+func getSharedOrigin(templates []*Template) string {
+	sharedOrigin := ""
+
+	for i, template := range templates {
+		if len(template.Host) == 0 {
+			return ""
+		}
+
+		origin, _ := splitOrigin(template.Host[0])
+		if origin == "" || (i > 0 && origin != sharedOrigin) {
+			return ""
+		}
+
+		sharedOrigin = origin
+	}
+
+	return sharedOrigin
+}
+
+func getSharedHeaders(templates []*Template) []string {
+	sharedHeaders := []string{}
+
+	for _, header := range templates[0].Headers {
+		if isBodyHeader(header) {
+			continue
+		}
+
+		shared := true
+		for _, template := range templates[1:] {
+			if !containsString(template.Headers, header) {
+				shared = false
+				break
+			}
+		}
+
+		if shared {
+			sharedHeaders = append(sharedHeaders, header)
+		}
+	}
+
+	return sharedHeaders
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func removeStrings(values, removeValues []string) []string {
+	keptValues := []string{}
+
+	for _, value := range values {
+		if !containsString(removeValues, value) {
+			keptValues = append(keptValues, value)
+		}
+	}
+
+	return keptValues
+}
+
+// SplitBase moves what all templates share (the scheme and host,
+// headers, auth, config and backend) into a base template that
+// each template includes. The templates are changed in place.
+func SplitBase(templates []*Template, baseFileName string, templateFileNames []string) (*Template, error) {
+	base := &Template{}
+	if len(templates) == 0 {
+		return base, nil
+	}
+
+	if sharedOrigin := getSharedOrigin(templates); sharedOrigin != "" {
+		base.Host = []string{sharedOrigin}
+
+		for _, template := range templates {
+			_, rest := splitOrigin(template.Host[0])
+			template.Host = append(optionalLine(rest), template.Host[1:]...)
+		}
+	}
+
+	base.Headers = getSharedHeaders(templates)
+
+	first := templates[0]
+	sharedAuth, sharedConfig, sharedBackend := true, true, true
+	for _, template := range templates[1:] {
+		sharedAuth = sharedAuth && template.Auth == first.Auth
+		sharedConfig = sharedConfig && reflect.DeepEqual(template.Config, first.Config)
+		sharedBackend = sharedBackend && template.Backend == first.Backend && reflect.DeepEqual(template.BackendOptions, first.BackendOptions)
+	}
+
+	if sharedAuth {
+		base.Auth = first.Auth
+	}
+
+	if sharedConfig {
+		base.Config = first.Config
+	}
+
+	if sharedBackend {
+		base.Backend, base.BackendOptions = first.Backend, first.BackendOptions
+	}
+
+	absBaseFileName, err := filepath.Abs(baseFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, template := range templates {
+		template.Headers = removeStrings(template.Headers, base.Headers)
+
+		if sharedAuth {
+			template.Auth = ""
+		}
+
+		if sharedConfig {
+			template.Config = nil
+		}
+
+		if sharedBackend {
+			template.Backend, template.BackendOptions = "", nil
+		}
+
+		absTemplateFileName, err := filepath.Abs(templateFileNames[i])
+		if err != nil {
+			return nil, err
+		}
+
+		includeFileName, err := filepath.Rel(filepath.Dir(absTemplateFileName), absBaseFileName)
+		if err != nil {
+			return nil, err
+		}
+
+		template.Include = append([]string{filepath.ToSlash(includeFileName)}, template.Include...)
+	}
+
+	return base, nil
+}
@@ -0,0 +1,57 @@
+package convert
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitBase(t *testing.T) {
+	getUser := &Template{
+		Host:    []string{"http://localhost:8080/users/1"},
+		Headers: []string{"Accept: application/json", "X-Trace: 1"},
+		Backend: "curl",
+	}
+
+	createUser := &Template{
+		Host:    []string{"http://localhost:8080/users"},
+		Headers: []string{"Accept: application/json", "Content-Type: application/json"},
+		Body:    []string{"{}"},
+		Backend: "curl",
+	}
+
+	base, err := SplitBase([]*Template{getUser, createUser}, "api/base.ain", []string{"api/users/get.ain", "api/create.ain"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedBase := &Template{
+		Host:    []string{"http://localhost:8080"},
+		Headers: []string{"Accept: application/json"},
+		Backend: "curl",
+	}
+
+	if !reflect.DeepEqual(base, expectedBase) {
+		t.Errorf("Expected base: %+v, Got: %+v", expectedBase, base)
+	}
+
+	expectedGetUser := &Template{
+		Include: []string{"../base.ain"},
+		Host:    []string{"/users/1"},
+		Headers: []string{"X-Trace: 1"},
+	}
+
+	if !reflect.DeepEqual(getUser, expectedGetUser) {
+		t.Errorf("Expected template: %+v, Got: %+v", expectedGetUser, getUser)
+	}
+
+	expectedCreateUser := &Template{
+		Include: []string{"base.ain"},
+		Host:    []string{"/users"},
+		Headers: []string{"Content-Type: application/json"},
+		Body:    []string{"{}"},
+	}
+
+	if !reflect.DeepEqual(createUser, expectedCreateUser) {
+		t.Errorf("Expected template: %+v, Got: %+v", expectedCreateUser, createUser)
+	}
+}
@@ -0,0 +1,221 @@
+package convert
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+const collectionBaseFileName = "base.ain"
+
+// EnvVariable is a variable for an imported .env file
+type EnvVariable struct {
+	Name  string
+	Value string
+}
+
+// NamedEnvFile is a .env file and the file name to write it to
+type NamedEnvFile struct {
+	FileName  string
+	Variables []EnvVariable
+}
+
+var plainEnvValueRe = regexp.MustCompile(`^[a-zA-Z0-9_./:@%+,=-]*$`)
+
+func (n NamedEnvFile) String() string {
+	var builder strings.Builder
+
+	for _, variable := range n.Variables {
+		value := variable.Value
+		if !plainEnvValueRe.MatchString(value) {
+			// The .env parser reads json escapes in double quotes
+			value, _ = marshalIndent(value)
+		}
+
+		builder.WriteString(variable.Name + "=" + value + "\n")
+	}
+
+	return builder.String()
+}
+
+func (n NamedEnvFile) has(name string) bool {
+	for _, variable := range n.Variables {
+		if variable.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Collection is the templates and .env files imported from an
+// API client, along with what could not be translated
+type Collection struct {
+	Templates    []NamedTemplate
+	EnvFiles     []NamedEnvFile
+	Untranslated []string
+}
+
+func (c *Collection) addUntranslated(where, format string, args ...interface{}) {
+	c.Untranslated = append(c.Untranslated, where+": "+fmt.Sprintf(format, args...))
+}
+
+// addVariable adds the variable to the env file
+// unless it's there with a different value
+func (c *Collection) addVariable(envFile *NamedEnvFile, where, name, value string) {
+	envVarName := ToEnvVarName(name)
+
+	for _, variable := range envFile.Variables {
+		if variable.Name != envVarName {
+			continue
+		}
+
+		if variable.Value != value {
+			c.addUntranslated(where, "variable %s is set to different values, kept the first one", name)
+		}
+
+		return
+	}
+
+	envFile.Variables = append(envFile.Variables, EnvVariable{Name: envVarName, Value: value})
+}
+
+// addTemplate adds the template in the directory, naming it
+// after the request and including the base template
+func (c *Collection) addTemplate(dirs []string, name string, template *Template) string {
+	dirNames := []string{}
+	for _, dir := range dirs {
+		if dirName := toFileName(dir); dirName != "" {
+			dirNames = append(dirNames, dirName)
+		}
+	}
+
+	fileName := toFileName(name)
+	if fileName == "" {
+		fileName = "request"
+	}
+
+	templateDir := path.Join(dirNames...)
+	templateFileName := path.Join(templateDir, fileName+".ain")
+
+	for suffix := 2; c.hasTemplate(templateFileName); suffix++ {
+		templateFileName = path.Join(templateDir, fmt.Sprintf("%s-%d.ain", fileName, suffix))
+	}
+
+	template.Include = []string{strings.Repeat("../", len(dirNames)) + collectionBaseFileName}
+	c.Templates = append(c.Templates, NamedTemplate{FileName: templateFileName, Template: template})
+
+	return templateFileName
+}
+
+func (c *Collection) hasTemplate(fileName string) bool {
+	for _, namedTemplate := range c.Templates {
+		if namedTemplate.FileName == fileName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getEnvFileName names the .env file after the environment, or after
+// its id when the name has no file name characters. A taken file name
+// gets a number appended, the same as for templates.
+func (c *Collection) getEnvFileName(name, id string) string {
+	fileName := toFileName(name)
+	if fileName == "" {
+		fileName = toFileName(id)
+	}
+
+	if fileName == "" {
+		fileName = "environment"
+	}
+
+	envFileName := ".env." + fileName
+	for suffix := 2; c.hasEnvFile(envFileName); suffix++ {
+		envFileName = fmt.Sprintf(".env.%s-%d", fileName, suffix)
+	}
+
+	return envFileName
+}
+
+func (c *Collection) hasEnvFile(fileName string) bool {
+	for _, envFile := range c.EnvFiles {
+		if envFile.FileName == fileName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// {{name}}, {{ name }} and the insomnia {{ _.name }}
+var mustacheVariableRe = regexp.MustCompile(`{{\s*(?:_\.)?([^{}\s]+)\s*}}`)
+
+// Dynamic variables that have an executable doing the same
+var dynamicVariableExecutables = map[string]string{
+	"$guid":       "$(uuidgen)",
+	"$randomUUID": "$(uuidgen)",
+	"$timestamp":  "$(date +%s)",
+	// JetBrains HTTP client
+	"$uuid":        "$(uuidgen)",
+	"$random.uuid": "$(uuidgen)",
+}
+
+// replaceEscapedVariables turns {{variables}} into ain ${VARIABLES}.
+// Escaping leaves the braces alone so it's done on escaped text.
+func (c *Collection) replaceEscapedVariables(where, text string) string {
+	return mustacheVariableRe.ReplaceAllStringFunc(text, func(variable string) string {
+		name := mustacheVariableRe.FindStringSubmatch(variable)[1]
+
+		if strings.HasPrefix(name, "$") {
+			if executable, found := dynamicVariableExecutables[name]; found {
+				return executable
+			}
+
+			c.addUntranslated(where, "dynamic variable %s", name)
+			return variable
+		}
+
+		return "${" + ToEnvVarName(name) + "}"
+	})
+}
+
+func (c *Collection) replaceVariables(where, text string) string {
+	return c.replaceEscapedVariables(where, Escape(text))
+}
+
+func (c *Collection) replaceVariablesInLines(where string, lines []string) []string {
+	replacedLines := []string{}
+	for _, line := range EscapeLines(lines) {
+		replacedLines = append(replacedLines, c.replaceEscapedVariables(where, line))
+	}
+
+	return replacedLines
+}
+
+// getBasicAuthHeader returns the header with the credentials encoded,
+// or false if they hold variables which are only known when calling
+func getBasicAuthHeader(username, password string) (string, bool) {
+	if mustacheVariableRe.MatchString(username) || mustacheVariableRe.MatchString(password) {
+		return "", false
+	}
+
+	credentials := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	return "Authorization: Basic " + credentials, true
+}
+
+// newCollection starts a collection with the base template
+// every imported template includes
+func newCollection(title string) *Collection {
+	base := &Template{Backend: "curl"}
+	if title != "" {
+		base.Comments = []string{title}
+	}
+
+	return &Collection{
+		Templates: []NamedTemplate{{FileName: collectionBaseFileName, Template: base}},
+	}
+}
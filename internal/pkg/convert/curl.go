@@ -0,0 +1,435 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/jonaslu/ain/internal/pkg/utils"
+	"github.com/pkg/errors"
+)
+
+// Options that take a value and are not translated,
+// so the value is kept with them in [BackendOptions]
+var curlOptionsWithValue = map[string]bool{
+	"-o": true, "--output": true,
+	"-w": true, "--write-out": true,
+	"-F": true, "--form": true, "--form-string": true,
+	"-T": true, "--upload-file": true,
+	"-r": true, "--range": true,
+	"-c": true, "--cookie-jar": true,
+	"-E": true, "--cert-type": true, "--key-type": true,
+	"--retry": true, "--retry-delay": true, "--retry-max-time": true,
+	"--resolve": true, "--connect-to": true,
+	"--limit-rate": true, "--interface": true,
+	"--proxy-user": true, "-U": true,
+	"--oauth2-bearer": true, "--aws-sigv4": true,
+	"-K": true, "--config": true,
+	"--trace": true, "--trace-ascii": true,
+	"--dns-servers": true, "--local-port": true,
+}
+
+// Options translated into [Config] that work for all backends
+var curlConfigOptions = map[string]string{
+	"-m":                "Timeout",
+	"--max-time":        "Timeout",
+	"--connect-timeout": "ConnectTimeout",
+	"--max-redirs":      "MaxRedirects",
+	"-x":                "Proxy",
+	"--proxy":           "Proxy",
+	"--cacert":          "CACert",
+	"--cert":            "ClientCert",
+	"--key":             "ClientKey",
+	"--unix-socket":     "UnixSocket",
+}
+
+var curlBoolConfigOptions = map[string]string{
+	"-k":           "Insecure",
+	"--insecure":   "Insecure",
+	"-L":           "FollowRedirects",
+	"--location":   "FollowRedirects",
+	"--compressed": "Compressed",
+	"-v":           "Verbose",
+	"--verbose":    "Verbose",
+}
+
+// Short options that can be grouped (e g -sSL)
+var curlShortFlagsRe = regexp.MustCompile(`^-[a-zA-Z0-9#:]{2,}$`)
+
+const curlShortFlagsWithValue = "XHdAebumxowFTrcEKU"
+
+var curlLineContinuationRe = regexp.MustCompile(`\\\r?\n`)
+
+var ansiCEscapes = map[byte]string{
+	'n': "\n", 't': "\t", 'r': "\r", 'a': "\a", 'b': "\b", 'e': "\x1b",
+	'f': "\f", 'v': "\v", '\\': "\\", '\'': "'", '"': "\"", '?': "?",
+}
+
+// replaceAnsiCQuotes turns the bash $'...' quoting devtools use
+// for text with newlines or quotes into plain single quotes
+func replaceAnsiCQuotes(commandLine string) string {
+	var builder strings.Builder
+
+	for i := 0; i < len(commandLine); i++ {
+		atWordStart := i == 0 || unicode.IsSpace(rune(commandLine[i-1]))
+		if !atWordStart || !strings.HasPrefix(commandLine[i:], "$'") {
+			builder.WriteByte(commandLine[i])
+			continue
+		}
+
+		builder.WriteByte('\'')
+
+		for i += 2; i < len(commandLine) && commandLine[i] != '\''; i++ {
+			if commandLine[i] != '\\' || i+1 == len(commandLine) {
+				builder.WriteByte(commandLine[i])
+				continue
+			}
+
+			i++
+			unescaped, found := ansiCEscapes[commandLine[i]]
+			switch {
+			case found:
+			case commandLine[i] == 'x' && i+2 < len(commandLine):
+				if hexValue, err := strconv.ParseUint(commandLine[i+1:i+3], 16, 8); err == nil {
+					unescaped = string(rune(hexValue))
+					i += 2
+				}
+			case commandLine[i] == 'u' && i+4 < len(commandLine):
+				if hexValue, err := strconv.ParseUint(commandLine[i+1:i+5], 16, 32); err == nil {
+					unescaped = string(rune(hexValue))
+					i += 4
+				}
+			}
+
+			if unescaped == "" {
+				unescaped = "\\" + string(commandLine[i])
+			}
+
+			// Quotes are escaped the way utils.TokenizeLine reads them
+			builder.WriteString(strings.ReplaceAll(unescaped, "'", "\\'"))
+		}
+
+		builder.WriteByte('\'')
+	}
+
+	return builder.String()
+}
+
+type curlCommand struct {
+	url            string
+	method         string
+	headers        []string
+	data           []curlData
+	getData        bool
+	auth           string
+	digest         bool
+	cookies        []string
+	config         []string
+	backendOptions []string
+}
+
+func (c *curlCommand) addConfig(key, value string) {
+	c.config = append(c.config, key+"="+value)
+}
+
+func splitShortFlags(args []string) []string {
+	splitArgs := []string{}
+
+	for _, arg := range args {
+		if !curlShortFlagsRe.MatchString(arg) {
+			splitArgs = append(splitArgs, arg)
+			continue
+		}
+
+		for i, flag := range arg[1:] {
+			splitArgs = append(splitArgs, "-"+string(flag))
+
+			// The rest is the value, e g -XPOST
+			if strings.ContainsRune(curlShortFlagsWithValue, flag) && i+2 < len(arg) {
+				splitArgs = append(splitArgs, arg[i+2:])
+				break
+			}
+		}
+	}
+
+	return splitArgs
+}
+
+// splitOptionValue splits --option=value into the option and value
+func splitOptionValue(arg string) (string, string, bool) {
+	if !strings.HasPrefix(arg, "--") {
+		return arg, "", false
+	}
+
+	optionParts := strings.SplitN(arg, "=", 2)
+	if len(optionParts) != 2 {
+		return arg, "", false
+	}
+
+	return optionParts[0], optionParts[1], true
+}
+
+type curlData struct {
+	option string
+	value  string
+}
+
+func (d curlData) isFile() bool {
+	return d.option != "--data-raw" && d.option != "--data-urlencode" && strings.HasPrefix(d.value, "@")
+}
+
+// getTemplateText returns the data as it's sent, escaped for the template
+func (d curlData) getTemplateText() string {
+	if d.isFile() {
+		// The file contents are read when calling
+		return "$(cat " + QuoteBackendOption(strings.TrimPrefix(d.value, "@")) + ")"
+	}
+
+	if d.option == "--data-urlencode" {
+		// name=content or content, where only the content is encoded
+		if nameAndContent := strings.SplitN(d.value, "=", 2); len(nameAndContent) == 2 {
+			return Escape(nameAndContent[0] + "=" + url.QueryEscape(nameAndContent[1]))
+		}
+
+		return Escape(url.QueryEscape(d.value))
+	}
+
+	return Escape(d.value)
+}
+
+func parseCurlCommand(commandLine string) (*curlCommand, error) {
+	commandLine = curlLineContinuationRe.ReplaceAllString(commandLine, " ")
+	commandLine = replaceAnsiCQuotes(commandLine)
+
+	args, err := utils.TokenizeLine(commandLine)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(args) == 0 || (args[0] != "curl" && !strings.HasSuffix(args[0], "/curl")) {
+		return nil, errors.New("not a curl command, it must start with curl")
+	}
+
+	args = splitShortFlags(args[1:])
+	command := &curlCommand{}
+
+	for i := 0; i < len(args); i++ {
+		option, value, hasValue := splitOptionValue(args[i])
+
+		nextValue := func() (string, error) {
+			if hasValue {
+				return value, nil
+			}
+
+			if i+1 >= len(args) {
+				return "", errors.Errorf("option %s needs a value", option)
+			}
+
+			i++
+			return args[i], nil
+		}
+
+		if !strings.HasPrefix(option, "-") {
+			if command.url != "" {
+				return nil, errors.Errorf("can only import one url, got %s and %s", command.url, option)
+			}
+
+			command.url = option
+			continue
+		}
+
+		if configKey, isBoolConfig := curlBoolConfigOptions[option]; isBoolConfig {
+			command.addConfig(configKey, "true")
+			continue
+		}
+
+		if configKey, isConfig := curlConfigOptions[option]; isConfig {
+			configValue, err := nextValue()
+			if err != nil {
+				return nil, err
+			}
+
+			// Ain timeouts are whole seconds
+			if configKey == "Timeout" || configKey == "ConnectTimeout" {
+				configValue = strings.SplitN(configValue, ".", 2)[0]
+			}
+
+			command.addConfig(configKey, configValue)
+			continue
+		}
+
+		switch option {
+		case "--http1.0", "--http1.1", "--http2", "--http3":
+			command.addConfig("HTTPVersion", strings.TrimPrefix(option, "--http"))
+		case "-X", "--request":
+			if command.method, err = nextValue(); err != nil {
+				return nil, err
+			}
+		case "-I", "--head":
+			command.method = "HEAD"
+		case "-G", "--get":
+			command.getData = true
+		case "--url":
+			if command.url, err = nextValue(); err != nil {
+				return nil, err
+			}
+		case "-H", "--header":
+			header, err := nextValue()
+			if err != nil {
+				return nil, err
+			}
+
+			command.headers = append(command.headers, header)
+		case "-A", "--user-agent", "-e", "--referer":
+			headerValue, err := nextValue()
+			if err != nil {
+				return nil, err
+			}
+
+			headerName := "User-Agent"
+			if option == "-e" || option == "--referer" {
+				headerName = "Referer"
+			}
+
+			command.headers = append(command.headers, headerName+": "+headerValue)
+		case "-d", "--data", "--data-ascii", "--data-binary", "--data-raw", "--data-urlencode", "--json":
+			data, err := nextValue()
+			if err != nil {
+				return nil, err
+			}
+
+			if option == "--json" {
+				command.headers = append(command.headers, "Content-Type: application/json", "Accept: application/json")
+			}
+
+			command.data = append(command.data, curlData{option: option, value: data})
+		case "-u", "--user":
+			if command.auth, err = nextValue(); err != nil {
+				return nil, err
+			}
+		case "--digest":
+			command.digest = true
+		case "--basic":
+			// Basic is the default auth scheme
+		case "-b", "--cookie":
+			cookie, err := nextValue()
+			if err != nil {
+				return nil, err
+			}
+
+			// Without an = it's a file to read cookies from
+			if !strings.Contains(cookie, "=") {
+				command.backendOptions = append(command.backendOptions, option, cookie)
+				continue
+			}
+
+			for _, nameValue := range strings.Split(cookie, ";") {
+				if nameValue = strings.TrimSpace(nameValue); nameValue != "" {
+					command.cookies = append(command.cookies, nameValue)
+				}
+			}
+		default:
+			if hasValue {
+				command.backendOptions = append(command.backendOptions, args[i])
+				continue
+			}
+
+			command.backendOptions = append(command.backendOptions, option)
+			if curlOptionsWithValue[option] {
+				optionValue, err := nextValue()
+				if err != nil {
+					return nil, err
+				}
+
+				command.backendOptions = append(command.backendOptions, optionValue)
+			}
+		}
+	}
+
+	if command.url == "" {
+		return nil, errors.New("no url found in the curl command")
+	}
+
+	return command, nil
+}
+
+// getBody pretty prints a json body
+// so it's easier to edit in the template
+func getBody(data string) []string {
+	var indentedBody bytes.Buffer
+	if json.Valid([]byte(data)) && json.Indent(&indentedBody, []byte(data), "", "  ") == nil {
+		data = indentedBody.String()
+	}
+
+	return strings.Split(data, "\n")
+}
+
+// FromCurl converts a curl command line (as copied
+// from browser devtools) into an ain template
+func FromCurl(commandLine string) (*Template, error) {
+	command, err := parseCurlCommand(commandLine)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not import curl command")
+	}
+
+	hostAndQuery := strings.SplitN(command.url, "?", 2)
+
+	template := &Template{
+		Host:    []string{Escape(hostAndQuery[0])},
+		Headers: EscapeLines(command.headers),
+		Cookies: EscapeLines(command.cookies),
+		Config:  command.config,
+		Backend: "curl",
+	}
+
+	if len(hostAndQuery) == 2 && hostAndQuery[1] != "" {
+		template.Query = EscapeLines(strings.Split(hostAndQuery[1], "&"))
+	}
+
+	if command.method != "" {
+		template.Method = Escape(strings.ToUpper(command.method))
+	}
+
+	dataTexts := []string{}
+	for _, data := range command.data {
+		dataTexts = append(dataTexts, data.getTemplateText())
+	}
+
+	switch {
+	case command.getData:
+		for _, dataText := range dataTexts {
+			template.Query = append(template.Query, strings.Split(dataText, "&")...)
+		}
+	case len(command.data) == 1 && !command.data[0].isFile() && command.data[0].option != "--data-urlencode":
+		template.Body = EscapeLines(getBody(command.data[0].value))
+	case len(command.data) > 0:
+		// Curl joins several data options with &
+		template.Body = strings.Split(strings.Join(dataTexts, "&"), "\n")
+	}
+
+	if command.auth != "" {
+		if !strings.Contains(command.auth, ":") {
+			// Curl prompts for the password
+			template.BackendOptions = append(template.BackendOptions, "-u "+QuoteBackendOption(command.auth))
+		} else if command.digest {
+			template.Auth = "digest " + Escape(command.auth)
+		} else {
+			template.Auth = "basic " + Escape(command.auth)
+		}
+	}
+
+	if len(command.backendOptions) > 0 {
+		quotedBackendOptions := []string{}
+		for _, backendOption := range command.backendOptions {
+			quotedBackendOptions = append(quotedBackendOptions, QuoteBackendOption(backendOption))
+		}
+
+		template.BackendOptions = append(template.BackendOptions, strings.Join(quotedBackendOptions, " "))
+	}
+
+	return template, nil
+}
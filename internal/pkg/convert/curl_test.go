@@ -0,0 +1,163 @@
+package convert
+
+import (
+	"testing"
+)
+
+func TestFromCurl(t *testing.T) {
+	tests := map[string]struct {
+		commandLine      string
+		expectedTemplate string
+	}{
+		"Copy as cURL from devtools": {
+			commandLine: `curl 'https://localhost:8080/api/users?page=2' \
+  -H 'accept: application/json' \
+  -H 'content-type: application/json' \
+  -b 'session=abc; theme=dark' \
+  --data-raw $'{"name":"it\'s","color":"#fff"}' \
+  --compressed`,
+			expectedTemplate: `[Host]
+https://localhost:8080/api/users
+
+[Query]
+page=2
+
+[Headers]
+accept: application/json
+content-type: application/json
+
+[Cookies]
+session=abc
+theme=dark
+
+[Body]
+{
+  "name": "it's",
+  "color": "` + "`" + `#fff"
+}
+
+[Config]
+Compressed=true
+
+[Backend]
+curl
+`,
+		},
+		"Grouped short flags and leftover options": {
+			commandLine: `curl -sSLk -XDELETE -u user:pass -o 'out file.json' --max-time 2.5 http://localhost/${ID}`,
+			expectedTemplate: `[Host]
+http://localhost/` + "`" + `${ID}
+
+[Method]
+DELETE
+
+[Auth]
+basic user:pass
+
+[Config]
+FollowRedirects=true
+Insecure=true
+Timeout=2
+
+[Backend]
+curl
+
+[BackendOptions]
+-s -S -o 'out file.json'
+`,
+		},
+		"Get with data and file data": {
+			commandLine: `curl -G --url http://localhost -d a=1 --data-urlencode 'q=a b'`,
+			expectedTemplate: `[Host]
+http://localhost
+
+[Query]
+a=1
+q=a+b
+
+[Backend]
+curl
+`,
+		},
+		"Bash quoted body with newlines": {
+			commandLine: `curl http://localhost --data-raw $'line 1\nit\'s \x41\u00e5\\n'`,
+			expectedTemplate: `[Host]
+http://localhost
+
+[Body]
+line 1
+it's Aå\n
+
+[Backend]
+curl
+`,
+		},
+		"Body from file": {
+			commandLine: `curl http://localhost -d @body.json -d x=1`,
+			expectedTemplate: `[Host]
+http://localhost
+
+[Body]
+$(cat body.json)&x=1
+
+[Backend]
+curl
+`,
+		},
+		"Single body from file": {
+			commandLine: `curl http://localhost -d @body.json`,
+			expectedTemplate: `[Host]
+http://localhost
+
+[Body]
+$(cat body.json)
+
+[Backend]
+curl
+`,
+		},
+		"Single url encoded data": {
+			commandLine: `curl http://localhost --data-urlencode 'q=a b&c'`,
+			expectedTemplate: `[Host]
+http://localhost
+
+[Body]
+q=a+b%26c
+
+[Backend]
+curl
+`,
+		},
+	}
+
+	for name, test := range tests {
+		template, err := FromCurl(test.commandLine)
+		if err != nil {
+			t.Errorf("Test: %s, Unexpected error: %v", name, err)
+			continue
+		}
+
+		if result := template.String(); result != test.expectedTemplate {
+			t.Errorf("Test: %s, Expected:\n%s\nGot:\n%s", name, test.expectedTemplate, result)
+		}
+	}
+}
+
+func TestFromCurlErrors(t *testing.T) {
+	tests := map[string]struct {
+		commandLine   string
+		expectedError string
+	}{
+		"Not curl":      {"wget http://localhost", "could not import curl command: not a curl command, it must start with curl"},
+		"No url":        {"curl -sS", "could not import curl command: no url found in the curl command"},
+		"Missing value": {"curl http://localhost -H", "could not import curl command: option -H needs a value"},
+		"Two urls":      {"curl http://a http://b", "could not import curl command: can only import one url, got http://a and http://b"},
+	}
+
+	for name, test := range tests {
+		_, err := FromCurl(test.commandLine)
+		if err == nil || err.Error() != test.expectedError {
+			t.Errorf("Test: %s, Expected error: %s, Got: %v", name, test.expectedError, err)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package convert
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+	"github.com/pkg/errors"
+)
+
+// Headers the browser (or curl) sets on its own. Accept-Encoding
+// is left out too or curl would print the compressed body.
+var harSkippedHeaders = map[string]bool{
+	"host":            true,
+	"content-length":  true,
+	"connection":      true,
+	"accept-encoding": true,
+}
+
+func matchesHARHost(requestUrl *url.URL, host string) bool {
+	if host == "" {
+		return true
+	}
+
+	return strings.EqualFold(requestUrl.Host, host) || strings.EqualFold(requestUrl.Hostname(), host)
+}
+
+func getHARBody(postData *data.HARPostData) []string {
+	if postData == nil {
+		return nil
+	}
+
+	if postData.Text != "" {
+		return EscapeLines(getBody(postData.Text))
+	}
+
+	formValues := []string{}
+	for _, param := range postData.Params {
+		formValues = append(formValues, url.QueryEscape(param.Name)+"="+url.QueryEscape(param.Value))
+	}
+
+	if len(formValues) == 0 {
+		return nil
+	}
+
+	return []string{Escape(strings.Join(formValues, "&"))}
+}
+
+func getHARTemplate(request data.HARRequest) *Template {
+	hostAndQuery := strings.SplitN(request.Url, "?", 2)
+
+	template := &Template{
+		Host:    []string{Escape(hostAndQuery[0])},
+		Backend: "curl",
+	}
+
+	if len(hostAndQuery) == 2 && hostAndQuery[1] != "" {
+		template.Query = EscapeLines(strings.Split(hostAndQuery[1], "&"))
+	}
+
+	if method := strings.ToUpper(request.Method); method != "GET" {
+		template.Method = method
+	}
+
+	for _, header := range request.Headers {
+		// HTTP/2 pseudo headers, e g :authority
+		if strings.HasPrefix(header.Name, ":") || harSkippedHeaders[strings.ToLower(header.Name)] {
+			continue
+		}
+
+		if strings.EqualFold(header.Name, "cookie") {
+			for _, cookie := range strings.Split(header.Value, ";") {
+				if cookie = strings.TrimSpace(cookie); cookie != "" {
+					template.Cookies = append(template.Cookies, Escape(cookie))
+				}
+			}
+
+			continue
+		}
+
+		template.Headers = append(template.Headers, Escape(header.Name+": "+header.Value))
+	}
+
+	template.Body = getHARBody(request.PostData)
+
+	return template
+}
+
+// FromHAR converts the requests in a HAR (as saved from browser
+// devtools) into a template each. Only requests to the host are
+// converted, unless it's empty.
+func FromHAR(harContents []byte, host string) ([]NamedTemplate, error) {
+	var har data.HAR
+	if err := json.Unmarshal(harContents, &har); err != nil {
+		return nil, errors.Wrap(err, "could not read the HAR")
+	}
+
+	namedTemplates := []NamedTemplate{}
+	usedFileNames := map[string]bool{}
+
+	for _, entry := range har.Log.Entries {
+		requestUrl, err := url.Parse(entry.Request.Url)
+		if err != nil || (requestUrl.Scheme != "http" && requestUrl.Scheme != "https") {
+			// Data urls, websockets and the like
+			continue
+		}
+
+		if !matchesHARHost(requestUrl, host) {
+			continue
+		}
+
+		fileName := toFileName(entry.Request.Method + " " + requestUrl.Path)
+		templateFileName := fileName + ".ain"
+		for suffix := 2; usedFileNames[templateFileName]; suffix++ {
+			templateFileName = fmt.Sprintf("%s-%d.ain", fileName, suffix)
+		}
+
+		usedFileNames[templateFileName] = true
+
+		namedTemplates = append(namedTemplates, NamedTemplate{
+			FileName: templateFileName,
+			Template: getHARTemplate(entry.Request),
+		})
+	}
+
+	if len(namedTemplates) == 0 {
+		if host != "" {
+			return nil, errors.Errorf("found no requests to %s in the HAR", host)
+		}
+
+		return nil, errors.New("found no requests in the HAR")
+	}
+
+	return namedTemplates, nil
+}
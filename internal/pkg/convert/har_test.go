@@ -0,0 +1,152 @@
+package convert
+
+import (
+	"testing"
+)
+
+const testHAR = `{
+  "log": {
+    "version": "1.2",
+    "creator": {"name": "WebInspector", "version": "537.36"},
+    "entries": [
+      {
+        "request": {
+          "method": "GET",
+          "url": "https://api.example.com/users?page=2&q=a%20b",
+          "headers": [
+            {"name": ":authority", "value": "api.example.com"},
+            {"name": "accept", "value": "application/json"},
+            {"name": "accept-encoding", "value": "gzip, br"},
+            {"name": "cookie", "value": "theme=dark; session=${abc}"}
+          ]
+        }
+      },
+      {
+        "request": {
+          "method": "GET",
+          "url": "https://cdn.example.com/app.js",
+          "headers": []
+        }
+      },
+      {
+        "request": {
+          "method": "POST",
+          "url": "https://api.example.com/users",
+          "headers": [
+            {"name": "Content-Type", "value": "application/json"},
+            {"name": "Content-Length", "value": "16"}
+          ],
+          "postData": {"mimeType": "application/json", "text": "{\"name\":\"goat\"}"}
+        }
+      },
+      {
+        "request": {
+          "method": "POST",
+          "url": "https://api.example.com/users",
+          "headers": [],
+          "postData": {
+            "mimeType": "application/x-www-form-urlencoded",
+            "params": [{"name": "name", "value": "a goat"}, {"name": "age", "value": "3"}]
+          }
+        }
+      },
+      {
+        "request": {
+          "method": "GET",
+          "url": "data:image/png;base64,iVBORw0KGgo=",
+          "headers": []
+        }
+      }
+    ]
+  }
+}`
+
+func TestFromHAR(t *testing.T) {
+	tests := map[string]struct {
+		host              string
+		expectedFileNames []string
+		expectedTemplates []string
+	}{
+		"Filtered by host": {
+			host:              "api.example.com",
+			expectedFileNames: []string{"get-users.ain", "post-users.ain", "post-users-2.ain"},
+			expectedTemplates: []string{`[Host]
+https://api.example.com/users
+
+[Query]
+page=2
+q=a%20b
+
+[Headers]
+accept: application/json
+
+[Cookies]
+theme=dark
+session=` + "`" + `${abc}
+
+[Backend]
+curl
+`, `[Host]
+https://api.example.com/users
+
+[Method]
+POST
+
+[Headers]
+Content-Type: application/json
+
+[Body]
+{
+  "name": "goat"
+}
+
+[Backend]
+curl
+`, `[Host]
+https://api.example.com/users
+
+[Method]
+POST
+
+[Body]
+name=a+goat&age=3
+
+[Backend]
+curl
+`},
+		},
+		"All hosts": {
+			expectedFileNames: []string{"get-users.ain", "get-app-js.ain", "post-users.ain", "post-users-2.ain"},
+		},
+	}
+
+	for name, test := range tests {
+		namedTemplates, err := FromHAR([]byte(testHAR), test.host)
+		if err != nil {
+			t.Errorf("Test: %s, Unexpected error: %v", name, err)
+			continue
+		}
+
+		if len(namedTemplates) != len(test.expectedFileNames) {
+			t.Errorf("Test: %s, Expected: %d templates, Got: %d", name, len(test.expectedFileNames), len(namedTemplates))
+			continue
+		}
+
+		for i, namedTemplate := range namedTemplates {
+			if namedTemplate.FileName != test.expectedFileNames[i] {
+				t.Errorf("Test: %s, Expected: %s, Got: %s", name, test.expectedFileNames[i], namedTemplate.FileName)
+			}
+
+			if len(test.expectedTemplates) > i && namedTemplate.Template.String() != test.expectedTemplates[i] {
+				t.Errorf("Test: %s, Expected:\n%s\nGot:\n%s", name, test.expectedTemplates[i], namedTemplate.Template.String())
+			}
+		}
+	}
+}
+
+func TestFromHARNoRequests(t *testing.T) {
+	_, err := FromHAR([]byte(testHAR), "other.example.com")
+	if err == nil || err.Error() != "found no requests to other.example.com in the HAR" {
+		t.Errorf("Test: No requests to host, Expected: error, Got: %v", err)
+	}
+}
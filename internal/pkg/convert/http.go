@@ -0,0 +1,324 @@
+package convert
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+	"github.com/jonaslu/ain/internal/pkg/parse"
+	"github.com/jonaslu/ain/internal/pkg/utils"
+	"github.com/pkg/errors"
+)
+
+// The same syntax is used by the VS Code REST Client and the JetBrains HTTP client
+
+// ### starts a request, any text after it is the name in JetBrains
+var httpRequestSeparatorRe = regexp.MustCompile(`^###(.*)$`)
+var httpCommentRe = regexp.MustCompile(`^\s*(#|//)`)
+var httpRequestNameRe = regexp.MustCompile(`^\s*(?:#|//)\s*@name\s+(\S+)`)
+var httpFileVariableRe = regexp.MustCompile(`^\s*@([^\s=]+)\s*=\s*(.*?)\s*$`)
+var httpRequestLineRe = regexp.MustCompile(`^(GET|POST|PUT|DELETE|PATCH|HEAD|OPTIONS|CONNECT|TRACE)\s+(.+?)(?:\s+HTTP/[0-9.]+)?$`)
+var httpQueryContinuationRe = regexp.MustCompile(`^\s+[?&]`)
+
+// {{$processEnv VAR}}, {{$dotenv VAR}} and the JetBrains {{$env.VAR}}
+var httpEnvVariableRe = regexp.MustCompile(`{{\s*\$(?:processEnv\s+%?|dotenv\s+%?|env\.)([^{}\s]+)\s*}}`)
+
+// References to other requests, e g {{login.response.body.$.token}}
+var httpRequestReferenceRe = regexp.MustCompile(`{{\s*[^{}\s]+\.(?:request|response)\.[^{}]*}}`)
+
+// Authorization headers the http clients encode from plain credentials
+var httpPlainAuthRe = regexp.MustCompile(`(?i)^authorization:\s*(basic|digest)\s+(\S+?)(?::|\s+)(.*)$`)
+
+type httpRequest struct {
+	name      string
+	comments  []string
+	method    string
+	url       string
+	headers   []string
+	bodyLines []string
+}
+
+func (c *Collection) replaceHttpEscapedVariables(where, text string) string {
+	for _, reference := range httpRequestReferenceRe.FindAllString(text, -1) {
+		c.addUntranslated(where, "request reference %s", reference)
+	}
+
+	text = httpEnvVariableRe.ReplaceAllString(text, "{{$1}}")
+	return c.replaceEscapedVariables(where, text)
+}
+
+func (c *Collection) replaceHttpVariables(where, text string) string {
+	return c.replaceHttpEscapedVariables(where, Escape(text))
+}
+
+func (c *Collection) getHttpTemplate(request *httpRequest) *Template {
+	where := request.name
+	template := &Template{Comments: request.comments}
+
+	hostAndQuery := strings.SplitN(request.url, "?", 2)
+	template.Host = []string{c.replaceHttpVariables(where, hostAndQuery[0])}
+
+	if len(hostAndQuery) == 2 {
+		for _, queryLine := range strings.Split(hostAndQuery[1], "&") {
+			if queryLine != "" {
+				template.Query = append(template.Query, c.replaceHttpVariables(where, queryLine))
+			}
+		}
+	}
+
+	if request.method != "GET" {
+		template.Method = request.method
+	}
+
+	for _, header := range request.headers {
+		// Plain credentials, but a username and password in base64 is sent as is
+		if plainAuth := httpPlainAuthRe.FindStringSubmatch(header); plainAuth != nil && plainAuth[3] != "" {
+			template.Auth = strings.ToLower(plainAuth[1]) + " " + c.replaceHttpVariables(where, plainAuth[2]+":"+plainAuth[3])
+			continue
+		}
+
+		template.Headers = append(template.Headers, c.replaceHttpVariables(where, header))
+	}
+
+	bodyLines := request.bodyLines
+
+	// Response handler scripts are the rest of the request
+	for i, bodyLine := range bodyLines {
+		if strings.HasPrefix(strings.TrimSpace(bodyLine), ">") {
+			c.addUntranslated(where, "response handler")
+			bodyLines = bodyLines[:i]
+			break
+		}
+	}
+
+	for len(bodyLines) > 0 && strings.TrimSpace(bodyLines[len(bodyLines)-1]) == "" {
+		bodyLines = bodyLines[:len(bodyLines)-1]
+	}
+
+	for _, bodyLine := range bodyLines {
+		trimmedBodyLine := strings.TrimSpace(bodyLine)
+
+		switch {
+		case strings.HasPrefix(trimmedBodyLine, "<>"):
+			// Reference to an earlier response, only used by the IDE
+		case strings.HasPrefix(trimmedBodyLine, "<@"), strings.HasPrefix(trimmedBodyLine, "< "):
+			fileName := strings.TrimSpace(strings.TrimLeft(trimmedBodyLine, "<@"))
+			if strings.HasPrefix(trimmedBodyLine, "<@") {
+				c.addUntranslated(where, "variables in the body file %s", fileName)
+			}
+
+			template.Body = append(template.Body, "$(cat "+QuoteBackendOption(fileName)+")")
+		default:
+			template.Body = append(template.Body, c.replaceHttpEscapedVariables(where, EscapeLines([]string{bodyLine})[0]))
+		}
+	}
+
+	return template
+}
+
+// splitHttpRequests reads the requests and file variables
+func splitHttpRequests(httpFileContents string) ([]*httpRequest, []EnvVariable) {
+	requests := []*httpRequest{}
+	variables := []EnvVariable{}
+
+	var request *httpRequest
+	inBody := false
+
+	startRequest := func(name string) {
+		request = &httpRequest{name: strings.TrimSpace(name)}
+		inBody = false
+	}
+
+	startRequest("")
+
+	for _, line := range strings.Split(strings.ReplaceAll(httpFileContents, "\r\n", "\n"), "\n") {
+		if separator := httpRequestSeparatorRe.FindStringSubmatch(line); separator != nil {
+			if request.url != "" {
+				requests = append(requests, request)
+			}
+
+			startRequest(separator[1])
+			continue
+		}
+
+		if inBody {
+			request.bodyLines = append(request.bodyLines, line)
+			continue
+		}
+
+		if request.url == "" {
+			if requestName := httpRequestNameRe.FindStringSubmatch(line); requestName != nil {
+				request.name = requestName[1]
+				continue
+			}
+
+			if httpCommentRe.MatchString(line) {
+				comment := strings.TrimSpace(httpCommentRe.ReplaceAllString(line, ""))
+				if comment != "" {
+					request.comments = append(request.comments, comment)
+				}
+
+				continue
+			}
+
+			if variable := httpFileVariableRe.FindStringSubmatch(line); variable != nil {
+				variables = append(variables, EnvVariable{Name: variable[1], Value: variable[2]})
+				continue
+			}
+
+			if trimmedLine := strings.TrimSpace(line); trimmedLine != "" {
+				request.method = "GET"
+				request.url = trimmedLine
+
+				if requestLine := httpRequestLineRe.FindStringSubmatch(trimmedLine); requestLine != nil {
+					request.method, request.url = requestLine[1], requestLine[2]
+				}
+			}
+
+			continue
+		}
+
+		switch {
+		case httpQueryContinuationRe.MatchString(line) && len(request.headers) == 0:
+			request.url += strings.TrimSpace(line)
+		case httpRequestNameRe.MatchString(line) || httpCommentRe.MatchString(line):
+		case strings.TrimSpace(line) == "":
+			inBody = true
+		default:
+			request.headers = append(request.headers, strings.TrimSpace(line))
+		}
+	}
+
+	if request.url != "" {
+		requests = append(requests, request)
+	}
+
+	return requests, variables
+}
+
+// FromHttpFile converts a .http file (VS Code REST Client or JetBrains
+// HTTP client) into a template per request and the file variables into a .env
+func FromHttpFile(httpFileContents []byte) (*Collection, error) {
+	requests, variables := splitHttpRequests(string(httpFileContents))
+	if len(requests) == 0 {
+		return nil, errors.New("found no requests in the .http file")
+	}
+
+	collection := newCollection("")
+	envFile := NamedEnvFile{FileName: ".env"}
+
+	for _, variable := range variables {
+		if mustacheVariableRe.MatchString(variable.Value) {
+			collection.addUntranslated("@"+variable.Name, "variable refers to other variables, which a .env file cannot")
+		}
+
+		collection.addVariable(&envFile, "@"+variable.Name, variable.Name, variable.Value)
+	}
+
+	for _, request := range requests {
+		name := request.name
+		if name == "" {
+			requestPath := httpRequestPathRe.FindStringSubmatch(mustacheVariableRe.ReplaceAllString(request.url, ""))[1]
+			name = strings.ToLower(request.method) + " " + strings.Trim(requestPath, "/")
+			request.name = name
+		}
+
+		collection.addTemplate(nil, name, collection.getHttpTemplate(request))
+	}
+
+	if len(envFile.Variables) > 0 {
+		collection.EnvFiles = append(collection.EnvFiles, envFile)
+	}
+
+	return collection, nil
+}
+
+// The path in a url without the scheme, host and query
+var httpRequestPathRe = regexp.MustCompile(`^(?:[a-zA-Z]+://)?(?:[^/?]*)([^?]*)`)
+
+// FormatHttpVariable formats an ain variable for a .http file
+func FormatHttpVariable(envVarKey string) string {
+	return "{{" + envVarKey + "}}"
+}
+
+// A body line read from a file, the http clients read it with < file
+var httpBodyFileRe = regexp.MustCompile(`^\$\(cat (.+)\)$`)
+
+// FormatHttpExecutable formats an ain executable for a .http file. The
+// http clients can't run commands so only the ones doing the same as a
+// dynamic variable or reading a body file are translated.
+func FormatHttpExecutable(executable string) (string, bool) {
+	executableAndArgs, err := utils.TokenizeLine(executable)
+	if err == nil && len(executableAndArgs) == 2 && executableAndArgs[0] == "date" && executableAndArgs[1] == "+%s" {
+		return "{{$timestamp}}", true
+	}
+
+	if err == nil && len(executableAndArgs) == 2 && executableAndArgs[0] == "cat" {
+		return "$(cat " + executableAndArgs[1] + ")", true
+	}
+
+	return "$(" + executable + ")", false
+}
+
+// ToHttpRequest formats the request as a named request for a .http file
+// and returns what could not be translated as the http clients lack it
+func ToHttpRequest(name string, request *parse.ExportRequest) (string, []string) {
+	untranslated := []string{}
+	var builder strings.Builder
+
+	builder.WriteString("### " + name + "\n")
+	builder.WriteString("# @name " + name + "\n")
+
+	method := request.Method
+	if method == "" {
+		method = "GET"
+	}
+
+	url := request.Host
+	if len(request.Query) > 0 {
+		url += "?" + strings.Join(request.Query, "&")
+	}
+
+	builder.WriteString(method + " " + url + "\n")
+
+	for _, header := range request.Headers {
+		builder.WriteString(header + "\n")
+	}
+
+	if request.Auth != nil {
+		switch request.Auth.Scheme {
+		case data.AuthBearer:
+			builder.WriteString(request.Auth.BearerHeader() + "\n")
+		case data.AuthBasic:
+			builder.WriteString("Authorization: Basic " + request.Auth.Username + " " + request.Auth.Password + "\n")
+		case data.AuthDigest:
+			builder.WriteString("Authorization: Digest " + request.Auth.Username + " " + request.Auth.Password + "\n")
+		}
+	}
+
+	if request.OAuth2 != nil {
+		untranslated = append(untranslated, "[OAuth2]")
+	}
+
+	if request.Sign != nil {
+		untranslated = append(untranslated, "[Sign]")
+	}
+
+	if len(request.BackendOptions) > 0 {
+		untranslated = append(untranslated, "[BackendOptions]")
+	}
+
+	if len(request.Body) > 0 {
+		builder.WriteString("\n")
+	}
+
+	for _, bodyLine := range request.Body {
+		if bodyFile := httpBodyFileRe.FindStringSubmatch(bodyLine); bodyFile != nil {
+			bodyLine = "< " + bodyFile[1]
+		}
+
+		builder.WriteString(bodyLine + "\n")
+	}
+
+	return builder.String(), untranslated
+}
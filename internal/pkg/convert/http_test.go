@@ -0,0 +1,189 @@
+package convert
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+	"github.com/jonaslu/ain/internal/pkg/parse"
+)
+
+const testHttpFile = `@baseUrl = https://api.example.com
+@token = abc 123
+
+### Get users
+GET {{baseUrl}}/users
+    ?page=2
+    &limit=10
+Accept: application/json
+Authorization: Bearer {{token}}
+
+###
+# @name createUser
+# Creates a user #1
+POST {{baseUrl}}/users HTTP/1.1
+Content-Type: application/json
+Authorization: Basic {{user}} {{$dotenv PASSWORD}}
+
+{
+  "id": "{{$guid}}",
+  "tag": "#1"
+}
+
+> {%
+  client.global.set("id", response.body.id);
+%}
+
+###
+PUT {{baseUrl}}/users/{{createUser.response.body.$.id}}
+
+< ./user.json
+`
+
+func TestFromHttpFile(t *testing.T) {
+	collection, err := FromHttpFile([]byte(testHttpFile))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedTemplates := []struct {
+		fileName string
+		template string
+	}{
+		{"base.ain", `[Backend]
+curl
+`},
+		{"get-users.ain", `[Include]
+base.ain
+
+[Host]
+${BASE_URL}/users
+
+[Query]
+page=2
+limit=10
+
+[Headers]
+Accept: application/json
+Authorization: Bearer ${TOKEN}
+`},
+		{"create-user.ain", `# Creates a user #1
+
+[Include]
+base.ain
+
+[Host]
+${BASE_URL}/users
+
+[Method]
+POST
+
+[Headers]
+Content-Type: application/json
+
+[Auth]
+basic ${USER}:${PASSWORD}
+
+[Body]
+{
+  "id": "$(uuidgen)",
+  "tag": "` + "`" + `#1"
+}
+`},
+		{"put-users.ain", `[Include]
+base.ain
+
+[Host]
+${BASE_URL}/users/${CREATE_USER_RESPONSE_BODY_ID}
+
+[Method]
+PUT
+
+[Body]
+$(cat ./user.json)
+`},
+	}
+
+	if len(collection.Templates) != len(expectedTemplates) {
+		t.Fatalf("Expected %d templates, Got: %d", len(expectedTemplates), len(collection.Templates))
+	}
+
+	for i, expected := range expectedTemplates {
+		if collection.Templates[i].FileName != expected.fileName {
+			t.Errorf("Expected file name: %s, Got: %s", expected.fileName, collection.Templates[i].FileName)
+		}
+
+		if result := collection.Templates[i].Template.String(); result != expected.template {
+			t.Errorf("Template: %s, Expected:\n%s\nGot:\n%s", expected.fileName, expected.template, result)
+		}
+	}
+
+	expectedEnvFile := "BASE_URL=https://api.example.com\nTOKEN=\"abc 123\"\n"
+	if len(collection.EnvFiles) != 1 || collection.EnvFiles[0].String() != expectedEnvFile {
+		t.Errorf("Expected .env:\n%s\nGot: %v", expectedEnvFile, collection.EnvFiles)
+	}
+
+	expectedUntranslated := []string{
+		"createUser: response handler",
+		"put users: request reference {{createUser.response.body.$.id}}",
+	}
+
+	if !reflect.DeepEqual(collection.Untranslated, expectedUntranslated) {
+		t.Errorf("Expected untranslated: %v, Got: %v", expectedUntranslated, collection.Untranslated)
+	}
+}
+
+func TestFromHttpFileNoRequests(t *testing.T) {
+	_, err := FromHttpFile([]byte("@host = localhost\n# Nothing here\n"))
+	if err == nil || err.Error() != "found no requests in the .http file" {
+		t.Errorf("Expected no requests error, Got: %v", err)
+	}
+}
+
+func TestToHttpRequest(t *testing.T) {
+	request := &parse.ExportRequest{
+		Host:           "{{HOST}}/users",
+		Query:          []string{"page=2", "limit=10"},
+		Method:         "POST",
+		Headers:        []string{"Content-Type: application/json"},
+		Body:           []string{"$(cat user.json)"},
+		Auth:           &data.Auth{Scheme: data.AuthBasic, Username: "{{USER}}", Password: "secret"},
+		BackendOptions: [][]string{{"-sS"}},
+	}
+
+	expected := `### create-user
+# @name create-user
+POST {{HOST}}/users?page=2&limit=10
+Content-Type: application/json
+Authorization: Basic {{USER}} secret
+
+< user.json
+`
+
+	result, untranslated := ToHttpRequest("create-user", request)
+	if result != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, result)
+	}
+
+	if !reflect.DeepEqual(untranslated, []string{"[BackendOptions]"}) {
+		t.Errorf("Expected untranslated: [[BackendOptions]], Got: %v", untranslated)
+	}
+}
+
+func TestFormatHttpExecutable(t *testing.T) {
+	tests := map[string]struct {
+		expected   string
+		translated bool
+	}{
+		"date +%s":            {"{{$timestamp}}", true},
+		"cat 'my user.json'":  {"$(cat my user.json)", true},
+		"pass show api/token": {"$(pass show api/token)", false},
+	}
+
+	for executable, test := range tests {
+		result, translated := FormatHttpExecutable(executable)
+		if result != test.expected || translated != test.translated {
+			t.Errorf("Test: %s, Expected: %s %v, Got: %s %v", executable, test.expected, test.translated, result, translated)
+		}
+	}
+}
@@ -0,0 +1,325 @@
+package convert
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type insomniaKeyValue struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled"`
+}
+
+type insomniaAuthentication struct {
+	Type     string `json:"type"`
+	Disabled bool   `json:"disabled"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Token    string `json:"token"`
+	Prefix   string `json:"prefix"`
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	AddTo    string `json:"addTo"`
+}
+
+type insomniaResource struct {
+	Id       string `json:"_id"`
+	Type     string `json:"_type"`
+	ParentId string `json:"parentId"`
+	Name     string `json:"name"`
+
+	// Requests
+	Method         string                  `json:"method"`
+	Url            string                  `json:"url"`
+	Headers        []insomniaKeyValue      `json:"headers"`
+	Parameters     []insomniaKeyValue      `json:"parameters"`
+	Authentication *insomniaAuthentication `json:"authentication"`
+	Body           struct {
+		MimeType string             `json:"mimeType"`
+		Text     string             `json:"text"`
+		Params   []insomniaKeyValue `json:"params"`
+	} `json:"body"`
+	PreRequestScript    string `json:"preRequestScript"`
+	AfterResponseScript string `json:"afterResponseScript"`
+
+	// Environments and folder environments
+	Data        map[string]interface{} `json:"data"`
+	Environment map[string]interface{} `json:"environment"`
+}
+
+type insomniaExport struct {
+	ExportFormat int                `json:"__export_format"`
+	Resources    []insomniaResource `json:"resources"`
+}
+
+// Template tags, e g {% response ... %} or {% uuid %}
+var insomniaTemplateTagRe = regexp.MustCompile(`{%.*?%}`)
+
+// flattenInsomniaEnvironment turns nested environment objects into
+// dotted names the same as they are referred to, e g {{ _.api.url }}
+func flattenInsomniaEnvironment(prefix string, data map[string]interface{}, variables map[string]string) {
+	for name, value := range data {
+		switch value := value.(type) {
+		case map[string]interface{}:
+			flattenInsomniaEnvironment(prefix+name+".", value, variables)
+		case string:
+			variables[prefix+name] = value
+		default:
+			valueJSON, _ := json.Marshal(value)
+			variables[prefix+name] = string(valueJSON)
+		}
+	}
+}
+
+func (c *Collection) addInsomniaEnvironment(envFile *NamedEnvFile, where string, data map[string]interface{}) {
+	variables := map[string]string{}
+	flattenInsomniaEnvironment("", data, variables)
+
+	// Maps are unordered, sort for the same .env every time
+	names := []string{}
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := variables[name]
+		if mustacheVariableRe.MatchString(value) || insomniaTemplateTagRe.MatchString(value) {
+			c.addUntranslated(where, "variable %s refers to other variables or template tags, which a .env file cannot", name)
+		}
+
+		c.addVariable(envFile, where, name, value)
+	}
+}
+
+func (c *Collection) replaceInsomniaVariables(where, text string) string {
+	for _, templateTag := range insomniaTemplateTagRe.FindAllString(text, -1) {
+		c.addUntranslated(where, "template tag %s", templateTag)
+	}
+
+	return c.replaceVariables(where, text)
+}
+
+func (c *Collection) addInsomniaAuth(where string, auth *insomniaAuthentication, template *Template) {
+	if auth.Disabled {
+		return
+	}
+
+	switch strings.ToLower(auth.Type) {
+	case "none", "":
+	case "bearer":
+		prefix := auth.Prefix
+		if prefix == "" {
+			prefix = "Bearer"
+		}
+
+		template.Headers = append(template.Headers, "Authorization: "+c.replaceInsomniaVariables(where, prefix+" "+auth.Token))
+	case "basic":
+		if header, ok := getBasicAuthHeader(auth.Username, auth.Password); ok {
+			template.Headers = append(template.Headers, header)
+			return
+		}
+
+		template.Auth = "basic " + c.replaceInsomniaVariables(where, auth.Username+":"+auth.Password)
+	case "digest":
+		template.Auth = "digest " + c.replaceInsomniaVariables(where, auth.Username+":"+auth.Password)
+	case "apikey":
+		key, value := c.replaceInsomniaVariables(where, auth.Key), c.replaceInsomniaVariables(where, auth.Value)
+		if auth.AddTo == "queryParams" {
+			template.Query = append(template.Query, key+"="+value)
+			return
+		}
+
+		template.Headers = append(template.Headers, key+": "+value)
+	default:
+		c.addUntranslated(where, "%s auth", auth.Type)
+	}
+}
+
+func (c *Collection) getInsomniaTemplate(where string, request *insomniaResource, auth *insomniaAuthentication) *Template {
+	template := &Template{}
+
+	host := request.Url
+	if queryStart := strings.Index(host, "?"); queryStart >= 0 {
+		for _, queryLine := range strings.Split(host[queryStart+1:], "&") {
+			if queryLine != "" {
+				template.Query = append(template.Query, c.replaceInsomniaVariables(where, queryLine))
+			}
+		}
+
+		host = host[:queryStart]
+	}
+
+	template.Host = []string{c.replaceInsomniaVariables(where, host)}
+
+	for _, parameter := range request.Parameters {
+		if !parameter.Disabled {
+			template.Query = append(template.Query, c.replaceInsomniaVariables(where, parameter.Name+"="+parameter.Value))
+		}
+	}
+
+	if method := strings.ToUpper(request.Method); method != "" && method != "GET" {
+		template.Method = method
+	}
+
+	hasContentType := false
+	for _, header := range request.Headers {
+		if header.Disabled || header.Name == "" {
+			continue
+		}
+
+		if strings.EqualFold(header.Name, "content-type") {
+			hasContentType = true
+		}
+
+		template.Headers = append(template.Headers, c.replaceInsomniaVariables(where, header.Name+": "+header.Value))
+	}
+
+	if auth != nil {
+		c.addInsomniaAuth(where, auth, template)
+	}
+
+	body := request.Body
+	contentType := body.MimeType
+
+	switch {
+	case body.MimeType == "application/x-www-form-urlencoded":
+		formValues := []string{}
+		for _, formValue := range body.Params {
+			if !formValue.Disabled {
+				formValues = append(formValues, formValue.Name+"="+formValue.Value)
+			}
+		}
+
+		if len(formValues) > 0 {
+			template.Body = []string{c.replaceInsomniaVariables(where, strings.Join(formValues, "&"))}
+		}
+	case body.MimeType == "multipart/form-data":
+		c.addUntranslated(where, "multipart form body")
+	case body.Text != "":
+		// The graphql body is already the json sent
+		if body.MimeType == "application/graphql" {
+			contentType = "application/json"
+		}
+
+		template.Body = c.replaceVariablesInLines(where, strings.Split(body.Text, "\n"))
+		if insomniaTemplateTagRe.MatchString(body.Text) {
+			c.addUntranslated(where, "template tags in the body")
+		}
+	}
+
+	if len(template.Body) > 0 && contentType != "" && !hasContentType {
+		template.Headers = append(template.Headers, "Content-Type: "+contentType)
+	}
+
+	if strings.TrimSpace(request.PreRequestScript) != "" {
+		c.addUntranslated(where, "pre-request script")
+	}
+
+	if strings.TrimSpace(request.AfterResponseScript) != "" {
+		c.addUntranslated(where, "after-response script")
+	}
+
+	return template
+}
+
+// FromInsomnia converts an Insomnia v4 export of one workspace into a
+// template per request in a directory per folder, the base environment
+// into a .env and each sub environment into a .env.<name>
+func FromInsomnia(exportContents []byte) (*Collection, error) {
+	var export insomniaExport
+	if err := json.Unmarshal(exportContents, &export); err != nil {
+		return nil, errors.Wrap(err, "could not read the Insomnia export")
+	}
+
+	if export.ExportFormat != 4 {
+		return nil, errors.New("only Insomnia v4 exports are supported, export the data as Insomnia v4 (JSON)")
+	}
+
+	resourcesById := map[string]*insomniaResource{}
+	workspaces := []*insomniaResource{}
+
+	for i := range export.Resources {
+		resource := &export.Resources[i]
+		resourcesById[resource.Id] = resource
+
+		if resource.Type == "workspace" {
+			workspaces = append(workspaces, resource)
+		}
+	}
+
+	if len(workspaces) != 1 {
+		return nil, errors.Errorf("the export has %d workspaces, export one workspace at a time", len(workspaces))
+	}
+
+	workspace := workspaces[0]
+	collection := newCollection(workspace.Name)
+	envFile := NamedEnvFile{FileName: ".env"}
+
+	// The base environment is the one on the workspace
+	// and the sub environments have it as parent
+	baseEnvironmentId := ""
+	for _, resource := range export.Resources {
+		if resource.Type == "environment" && resource.ParentId == workspace.Id {
+			baseEnvironmentId = resource.Id
+			collection.addInsomniaEnvironment(&envFile, resource.Name, resource.Data)
+		}
+	}
+
+	for _, resource := range export.Resources {
+		if resource.Type == "request_group" {
+			collection.addInsomniaEnvironment(&envFile, resource.Name, resource.Environment)
+		}
+	}
+
+	for i := range export.Resources {
+		request := &export.Resources[i]
+		if request.Type != "request" {
+			continue
+		}
+
+		dirs := []string{}
+		auth := request.Authentication
+
+		for parent := resourcesById[request.ParentId]; parent != nil && parent.Type == "request_group"; parent = resourcesById[parent.ParentId] {
+			dirs = append([]string{parent.Name}, dirs...)
+
+			// Requests without authentication inherit the closest folder's
+			if (auth == nil || auth.Type == "") && parent.Authentication != nil {
+				auth = parent.Authentication
+			}
+		}
+
+		where := strings.Join(append(append([]string{}, dirs...), request.Name), "/")
+		collection.addTemplate(dirs, request.Name, collection.getInsomniaTemplate(where, request, auth))
+	}
+
+	if len(envFile.Variables) > 0 {
+		collection.EnvFiles = append(collection.EnvFiles, envFile)
+	}
+
+	for _, resource := range export.Resources {
+		if resource.Type != "environment" || resource.ParentId != baseEnvironmentId || baseEnvironmentId == "" {
+			continue
+		}
+
+		subEnvFile := NamedEnvFile{FileName: collection.getEnvFileName(resource.Name, resource.Id)}
+		collection.addInsomniaEnvironment(&subEnvFile, resource.Name, resource.Data)
+
+		// Sub environments are layered on the base environment
+		for _, variable := range envFile.Variables {
+			if !subEnvFile.has(variable.Name) {
+				subEnvFile.Variables = append(subEnvFile.Variables, variable)
+			}
+		}
+
+		collection.EnvFiles = append(collection.EnvFiles, subEnvFile)
+	}
+
+	return collection, nil
+}
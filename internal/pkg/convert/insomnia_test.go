@@ -0,0 +1,157 @@
+package convert
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testInsomniaExport = `{"_type": "export", "__export_format": 4, "resources": [
+  {"_id": "wrk_1", "_type": "workspace", "name": "Shop"},
+  {"_id": "env_1", "_type": "environment", "parentId": "wrk_1", "name": "Base Environment", "data": {"base_url": "http://localhost", "api": {"key": "abc"}}},
+  {"_id": "env_2", "_type": "environment", "parentId": "env_1", "name": "Production", "data": {"base_url": "https://shop.example.com"}},
+  {"_id": "fld_1", "_type": "request_group", "parentId": "wrk_1", "name": "Orders", "environment": {"page": 1},
+    "authentication": {"type": "apikey", "key": "X-Api-Key", "value": "{{ _.api.key }}", "addTo": "header"}},
+  {"_id": "req_1", "_type": "request", "parentId": "fld_1", "name": "List orders", "method": "GET",
+    "url": "{{ _.base_url }}/orders?page={{ _.page }}", "parameters": [{"name": "sort", "value": "desc"}, {"name": "off", "value": "1", "disabled": true}], "authentication": {}, "body": {}},
+  {"_id": "req_2", "_type": "request", "parentId": "fld_1", "name": "Create order", "method": "POST", "url": "{{ _.base_url }}/orders",
+    "authentication": {"type": "bearer", "token": "{% response 'body', 'req_3' %}"},
+    "body": {"mimeType": "application/json", "text": "{\"item\": 1}"}, "preRequestScript": "insomnia.x()"},
+  {"_id": "req_3", "_type": "request", "parentId": "wrk_1", "name": "Login", "method": "POST", "url": "{{ _.base_url }}/login",
+    "authentication": {"type": "basic", "username": "{{ _.user }}", "password": "pw"},
+    "body": {"mimeType": "application/x-www-form-urlencoded", "params": [{"name": "a", "value": "1"}]}}
+]}`
+
+func TestFromInsomnia(t *testing.T) {
+	collection, err := FromInsomnia([]byte(testInsomniaExport))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedTemplates := []struct {
+		fileName string
+		template string
+	}{
+		{"base.ain", `# Shop
+
+[Backend]
+curl
+`},
+		{"orders/list-orders.ain", `[Include]
+../base.ain
+
+[Host]
+${BASE_URL}/orders
+
+[Query]
+page=${PAGE}
+sort=desc
+
+[Headers]
+X-Api-Key: ${API_KEY}
+`},
+		{"orders/create-order.ain", `[Include]
+../base.ain
+
+[Host]
+${BASE_URL}/orders
+
+[Method]
+POST
+
+[Headers]
+Authorization: Bearer {% response 'body', 'req_3' %}
+Content-Type: application/json
+
+[Body]
+{"item": 1}
+`},
+		{"login.ain", `[Include]
+base.ain
+
+[Host]
+${BASE_URL}/login
+
+[Method]
+POST
+
+[Headers]
+Content-Type: application/x-www-form-urlencoded
+
+[Auth]
+basic ${USER}:pw
+
+[Body]
+a=1
+`},
+	}
+
+	if len(collection.Templates) != len(expectedTemplates) {
+		t.Fatalf("Expected %d templates, Got: %d", len(expectedTemplates), len(collection.Templates))
+	}
+
+	for i, expected := range expectedTemplates {
+		if collection.Templates[i].FileName != expected.fileName {
+			t.Errorf("Expected file name: %s, Got: %s", expected.fileName, collection.Templates[i].FileName)
+		}
+
+		if result := collection.Templates[i].Template.String(); result != expected.template {
+			t.Errorf("Template: %s, Expected:\n%s\nGot:\n%s", expected.fileName, expected.template, result)
+		}
+	}
+
+	expectedEnvFiles := map[string]string{
+		".env":            "API_KEY=abc\nBASE_URL=http://localhost\nPAGE=1\n",
+		".env.production": "BASE_URL=https://shop.example.com\nAPI_KEY=abc\nPAGE=1\n",
+	}
+
+	if len(collection.EnvFiles) != len(expectedEnvFiles) {
+		t.Fatalf("Expected %d .env files, Got: %d", len(expectedEnvFiles), len(collection.EnvFiles))
+	}
+
+	for _, envFile := range collection.EnvFiles {
+		if result := envFile.String(); result != expectedEnvFiles[envFile.FileName] {
+			t.Errorf("Env file: %s, Expected:\n%s\nGot:\n%s", envFile.FileName, expectedEnvFiles[envFile.FileName], result)
+		}
+	}
+
+	expectedUntranslated := []string{
+		"Orders/Create order: template tag {% response 'body', 'req_3' %}",
+		"Orders/Create order: pre-request script",
+	}
+
+	if !reflect.DeepEqual(collection.Untranslated, expectedUntranslated) {
+		t.Errorf("Expected untranslated: %v, Got: %v", expectedUntranslated, collection.Untranslated)
+	}
+}
+
+func TestFromInsomniaOneWorkspace(t *testing.T) {
+	_, err := FromInsomnia([]byte(`{"__export_format": 4, "resources": []}`))
+	if err == nil || err.Error() != "the export has 0 workspaces, export one workspace at a time" {
+		t.Errorf("Expected workspace error, Got: %v", err)
+	}
+}
+
+func TestFromInsomniaEnvironmentFileNames(t *testing.T) {
+	collection, err := FromInsomnia([]byte(`{"__export_format": 4, "resources": [
+  {"_id": "wrk_1", "_type": "workspace", "name": "Shop"},
+  {"_id": "env_1", "_type": "environment", "parentId": "wrk_1", "name": "Base Environment", "data": {"a": "1"}},
+  {"_id": "env_2", "_type": "environment", "parentId": "env_1", "name": "", "data": {"a": "2"}},
+  {"_id": "env_3", "_type": "environment", "parentId": "env_1", "name": "???", "data": {"a": "3"}},
+  {"_id": "env_4", "_type": "environment", "parentId": "env_1", "name": "Staging", "data": {"a": "4"}},
+  {"_id": "env_5", "_type": "environment", "parentId": "env_1", "name": "staging", "data": {"a": "5"}}
+]}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedFileNames := []string{".env", ".env.env-2", ".env.env-3", ".env.staging", ".env.staging-2"}
+
+	fileNames := []string{}
+	for _, envFile := range collection.EnvFiles {
+		fileNames = append(fileNames, envFile.FileName)
+	}
+
+	if !reflect.DeepEqual(fileNames, expectedFileNames) {
+		t.Errorf("Expected: %v, Got: %v", expectedFileNames, fileNames)
+	}
+}
@@ -0,0 +1,57 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+type orderedKeyValue struct {
+	key   string
+	value interface{}
+}
+
+// orderedObject is a JSON object that keeps the order of its keys
+type orderedObject []orderedKeyValue
+
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString("{")
+
+	for i, keyValue := range o {
+		if i > 0 {
+			buffer.WriteString(",")
+		}
+
+		keyJSON, err := json.Marshal(keyValue.key)
+		if err != nil {
+			return nil, err
+		}
+
+		valueJSON, err := json.Marshal(keyValue.value)
+		if err != nil {
+			return nil, err
+		}
+
+		buffer.Write(keyJSON)
+		buffer.WriteString(":")
+		buffer.Write(valueJSON)
+	}
+
+	buffer.WriteString("}")
+
+	return buffer.Bytes(), nil
+}
+
+func marshalIndent(value interface{}) (string, error) {
+	var buffer bytes.Buffer
+
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(value); err != nil {
+		return "", err
+	}
+
+	return string(bytes.TrimSuffix(buffer.Bytes(), []byte("\n"))), nil
+}
@@ -0,0 +1,533 @@
+package convert
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const openAPIBaseFileName = "base.ain"
+
+// Depth to generate nested example bodies from a schema
+const maxSchemaExampleDepth = 8
+
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+var openAPIPathParamRe = regexp.MustCompile(`{([^}]+)}`)
+
+type openAPISpec struct {
+	root *yaml.Node
+}
+
+// getMapValue returns the value of the key in a mapping node
+func getMapValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+func getMapString(node *yaml.Node, key string) string {
+	if value := getMapValue(node, key); value != nil && value.Kind == yaml.ScalarNode {
+		return value.Value
+	}
+
+	return ""
+}
+
+// resolve follows any local $ref (e g #/components/schemas/Pet)
+func (s *openAPISpec) resolve(node *yaml.Node) *yaml.Node {
+	for followed := 0; node != nil && followed < maxSchemaExampleDepth; followed++ {
+		ref := getMapString(node, "$ref")
+		if !strings.HasPrefix(ref, "#/") {
+			return node
+		}
+
+		node = s.root
+		for _, refPart := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			refPart = strings.ReplaceAll(strings.ReplaceAll(refPart, "~1", "/"), "~0", "~")
+			node = getMapValue(node, refPart)
+		}
+	}
+
+	return node
+}
+
+// toJSONValue converts a yaml node into values that marshal
+// to JSON with the keys in the same order as in the spec
+func toJSONValue(node *yaml.Node) interface{} {
+	if node == nil {
+		return nil
+	}
+
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		object := orderedObject{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			object = append(object, orderedKeyValue{node.Content[i].Value, toJSONValue(node.Content[i+1])})
+		}
+
+		return object
+	case yaml.SequenceNode:
+		array := []interface{}{}
+		for _, itemNode := range node.Content {
+			array = append(array, toJSONValue(itemNode))
+		}
+
+		return array
+	}
+
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return node.Value
+	}
+
+	return value
+}
+
+// getSchemaValue returns the example, default
+// or first enum value given in the schema
+func getSchemaValue(schema *yaml.Node) (interface{}, bool) {
+	for _, exampleKey := range []string{"example", "default"} {
+		if example := getMapValue(schema, exampleKey); example != nil {
+			return toJSONValue(example), true
+		}
+	}
+
+	if enum := getMapValue(schema, "enum"); enum != nil && len(enum.Content) > 0 {
+		return toJSONValue(enum.Content[0]), true
+	}
+
+	return nil, false
+}
+
+// getSchemaExample builds an example from the schema
+// for values without an example of their own
+func (s *openAPISpec) getSchemaExample(schema *yaml.Node, depth int) interface{} {
+	schema = s.resolve(schema)
+	if schema == nil || depth > maxSchemaExampleDepth {
+		return nil
+	}
+
+	if value, found := getSchemaValue(schema); found {
+		return value
+	}
+
+	for _, combinedKey := range []string{"allOf", "oneOf", "anyOf"} {
+		combined := getMapValue(schema, combinedKey)
+		if combined == nil || len(combined.Content) == 0 {
+			continue
+		}
+
+		if combinedKey != "allOf" {
+			return s.getSchemaExample(combined.Content[0], depth+1)
+		}
+
+		object := orderedObject{}
+		for _, subSchema := range combined.Content {
+			if subObject, ok := s.getSchemaExample(subSchema, depth+1).(orderedObject); ok {
+				object = append(object, subObject...)
+			}
+		}
+
+		return object
+	}
+
+	schemaType := getMapString(schema, "type")
+	properties := getMapValue(schema, "properties")
+
+	switch {
+	case schemaType == "object" || properties != nil:
+		object := orderedObject{}
+		if properties != nil {
+			for i := 0; i+1 < len(properties.Content); i += 2 {
+				object = append(object, orderedKeyValue{properties.Content[i].Value, s.getSchemaExample(properties.Content[i+1], depth+1)})
+			}
+		}
+
+		return object
+	case schemaType == "array":
+		if items := getMapValue(schema, "items"); items != nil {
+			return []interface{}{s.getSchemaExample(items, depth+1)}
+		}
+
+		return []interface{}{}
+	case schemaType == "integer" || schemaType == "number":
+		return 0
+	case schemaType == "boolean":
+		return false
+	case schemaType == "string":
+		if format := getMapString(schema, "format"); format != "" {
+			return format
+		}
+
+		return "string"
+	}
+
+	return nil
+}
+
+// getExample returns the example of a parameter or media
+// type, or an example built from the schema
+func (s *openAPISpec) getExample(node *yaml.Node) (interface{}, bool) {
+	if example := getMapValue(node, "example"); example != nil {
+		return toJSONValue(example), true
+	}
+
+	if examples := getMapValue(node, "examples"); examples != nil && len(examples.Content) >= 2 {
+		if value := getMapValue(s.resolve(examples.Content[1]), "value"); value != nil {
+			return toJSONValue(value), true
+		}
+	}
+
+	schema := s.resolve(getMapValue(node, "schema"))
+	if value, found := getSchemaValue(schema); found {
+		return value, true
+	}
+
+	return s.getSchemaExample(schema, 0), false
+}
+
+func getExampleText(example interface{}) string {
+	if example == nil {
+		return ""
+	}
+
+	if exampleStr, ok := example.(string); ok {
+		return exampleStr
+	}
+
+	exampleJSON, err := marshalIndent(example)
+	if err != nil {
+		return fmt.Sprint(example)
+	}
+
+	return exampleJSON
+}
+
+// getServerUrl returns the first server with any variables
+// set to their default value
+func (s *openAPISpec) getServerUrl() string {
+	servers := getMapValue(s.root, "servers")
+	if servers == nil || len(servers.Content) == 0 {
+		return "${BASE_URL}"
+	}
+
+	server := servers.Content[0]
+	serverUrl := getMapString(server, "url")
+	variables := getMapValue(server, "variables")
+
+	serverUrl = openAPIPathParamRe.ReplaceAllStringFunc(serverUrl, func(variable string) string {
+		variableName := strings.Trim(variable, "{}")
+		if defaultValue := getMapString(getMapValue(variables, variableName), "default"); defaultValue != "" {
+			return defaultValue
+		}
+
+		return "${" + ToEnvVarName(variableName) + "}"
+	})
+
+	// A relative server url is relative to where the spec is served from
+	if !strings.Contains(serverUrl, "://") && !strings.HasPrefix(serverUrl, "${") {
+		serverUrl = "${BASE_URL}" + serverUrl
+	}
+
+	return strings.TrimSuffix(serverUrl, "/")
+}
+
+type securityHeaders struct {
+	headers  []string
+	query    []string
+	comments []string
+}
+
+// getSecurity returns the headers (or query parameters) for the
+// first security requirement, with the secrets as ${VARIABLES}
+func (s *openAPISpec) getSecurity(security *yaml.Node) securityHeaders {
+	result := securityHeaders{}
+	if security == nil || len(security.Content) == 0 {
+		return result
+	}
+
+	securitySchemes := getMapValue(getMapValue(s.root, "components"), "securitySchemes")
+	requirement := security.Content[0]
+
+	for i := 0; i+1 < len(requirement.Content); i += 2 {
+		schemeName := requirement.Content[i].Value
+		scheme := s.resolve(getMapValue(securitySchemes, schemeName))
+		if scheme == nil {
+			continue
+		}
+
+		envVar := "${" + ToEnvVarName(schemeName) + "}"
+
+		switch getMapString(scheme, "type") {
+		case "apiKey":
+			keyName := getMapString(scheme, "name")
+			switch getMapString(scheme, "in") {
+			case "header":
+				result.headers = append(result.headers, keyName+": "+envVar)
+			case "query":
+				result.query = append(result.query, keyName+"="+envVar)
+			case "cookie":
+				result.headers = append(result.headers, "Cookie: "+keyName+"="+envVar)
+			}
+		case "http":
+			if strings.EqualFold(getMapString(scheme, "scheme"), "basic") {
+				result.headers = append(result.headers, "Authorization: Basic "+envVar)
+				result.comments = append(result.comments, envVar+" is the base64 encoded <username>:<password>")
+				continue
+			}
+
+			result.headers = append(result.headers, "Authorization: Bearer "+envVar)
+		case "oauth2", "openIdConnect":
+			result.headers = append(result.headers, "Authorization: Bearer "+envVar)
+			result.comments = append(result.comments, envVar+" is an access token from the "+schemeName+" flow")
+		}
+	}
+
+	return result
+}
+
+type openAPIParameter struct {
+	name     string
+	in       string
+	required bool
+	node     *yaml.Node
+}
+
+// getParameters returns the path item parameters
+// overridden by the operation parameters
+func (s *openAPISpec) getParameters(pathItem, operation *yaml.Node) []openAPIParameter {
+	parameters := []openAPIParameter{}
+
+	for _, parametersNode := range []*yaml.Node{getMapValue(pathItem, "parameters"), getMapValue(operation, "parameters")} {
+		if parametersNode == nil {
+			continue
+		}
+
+	NextParameter:
+		for _, parameterNode := range parametersNode.Content {
+			parameterNode = s.resolve(parameterNode)
+			parameter := openAPIParameter{
+				name:     getMapString(parameterNode, "name"),
+				in:       getMapString(parameterNode, "in"),
+				required: getMapString(parameterNode, "required") == "true",
+				node:     parameterNode,
+			}
+
+			for i, existingParameter := range parameters {
+				if existingParameter.name == parameter.name && existingParameter.in == parameter.in {
+					parameters[i] = parameter
+					continue NextParameter
+				}
+			}
+
+			parameters = append(parameters, parameter)
+		}
+	}
+
+	return parameters
+}
+
+var nonFileNameRe = regexp.MustCompile(`[^a-z0-9]+`)
+var camelCaseRe = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+
+// toFileName turns an operation id or a tag into a file name
+func toFileName(name string) string {
+	name = camelCaseRe.ReplaceAllString(name, "$1-$2")
+	return strings.Trim(nonFileNameRe.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// ToEnvVarName turns a name (e g petId) into an
+// environment variable name (e g PET_ID)
+func ToEnvVarName(name string) string {
+	return strings.ToUpper(strings.ReplaceAll(toFileName(name), "-", "_"))
+}
+
+func (s *openAPISpec) getOperationTemplate(method, apiPath string, pathItem, operation *yaml.Node, globalSecurity *yaml.Node) *Template {
+	template := &Template{}
+
+	if summary := getMapString(operation, "summary"); summary != "" {
+		template.Comments = append(template.Comments, summary)
+	}
+
+	hostPath := openAPIPathParamRe.ReplaceAllStringFunc(Escape(apiPath), func(pathParam string) string {
+		return "${" + ToEnvVarName(strings.Trim(pathParam, "{}")) + "}"
+	})
+	template.Host = []string{hostPath}
+
+	optionalParameters := []string{}
+	for _, parameter := range s.getParameters(pathItem, operation) {
+		if parameter.in == "path" {
+			if description := getMapString(parameter.node, "description"); description != "" {
+				template.Comments = append(template.Comments, fmt.Sprintf("${%s}: %s", ToEnvVarName(parameter.name), description))
+			}
+
+			continue
+		}
+
+		example, found := s.getExample(parameter.node)
+		value := Escape(getExampleText(example))
+
+		if !found && !parameter.required {
+			optionalParameters = append(optionalParameters, parameter.name)
+			continue
+		}
+
+		if !found {
+			value = "${" + ToEnvVarName(parameter.name) + "}"
+		}
+
+		switch parameter.in {
+		case "query":
+			template.Query = append(template.Query, Escape(parameter.name)+"="+value)
+		case "header":
+			template.Headers = append(template.Headers, Escape(parameter.name)+": "+value)
+		case "cookie":
+			template.Cookies = append(template.Cookies, Escape(parameter.name)+"="+value)
+		}
+	}
+
+	if len(optionalParameters) > 0 {
+		template.Comments = append(template.Comments, "Optional parameters: "+strings.Join(optionalParameters, ", "))
+	}
+
+	// Security set on the operation overrides the security in base.ain
+	if security := getMapValue(operation, "security"); security != nil && !reflect.DeepEqual(toJSONValue(security), toJSONValue(globalSecurity)) {
+		operationSecurity := s.getSecurity(security)
+		template.Headers = append(template.Headers, operationSecurity.headers...)
+		template.Query = append(template.Query, operationSecurity.query...)
+		template.Comments = append(template.Comments, operationSecurity.comments...)
+	}
+
+	if method != "get" {
+		template.Method = strings.ToUpper(method)
+	}
+
+	requestBodyContent := getMapValue(s.resolve(getMapValue(operation, "requestBody")), "content")
+	if requestBodyContent != nil && len(requestBodyContent.Content) >= 2 {
+		// Prefer json if there's a choice
+		contentType, mediaType := requestBodyContent.Content[0].Value, requestBodyContent.Content[1]
+		if jsonMediaType := getMapValue(requestBodyContent, "application/json"); jsonMediaType != nil {
+			contentType, mediaType = "application/json", jsonMediaType
+		}
+
+		template.Headers = append(template.Headers, "Content-Type: "+contentType)
+
+		if example, _ := s.getExample(mediaType); example != nil {
+			template.Body = EscapeLines(strings.Split(getExampleText(example), "\n"))
+		}
+	}
+
+	return template
+}
+
+// FromOpenAPI converts an OpenAPI 3 spec (yaml or json) into a
+// base.ain with the server and security and one template per
+// operation in a directory per tag, each including base.ain
+func FromOpenAPI(specContents []byte) ([]NamedTemplate, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(specContents, &root); err != nil {
+		return nil, errors.Wrap(err, "could not read the OpenAPI spec")
+	}
+
+	spec := &openAPISpec{root: &root}
+
+	openAPIVersion := getMapString(spec.root, "openapi")
+	if !strings.HasPrefix(openAPIVersion, "3.") {
+		return nil, errors.New("only OpenAPI 3 specs are supported, the spec has no openapi: 3.x version")
+	}
+
+	globalSecurity := getMapValue(spec.root, "security")
+	security := spec.getSecurity(globalSecurity)
+
+	base := &Template{
+		Host:    []string{spec.getServerUrl()},
+		Query:   security.query,
+		Headers: security.headers,
+		Backend: "curl",
+	}
+
+	if title := getMapString(getMapValue(spec.root, "info"), "title"); title != "" {
+		base.Comments = append(base.Comments, title)
+	}
+
+	base.Comments = append(base.Comments, security.comments...)
+
+	namedTemplates := []NamedTemplate{{FileName: openAPIBaseFileName, Template: base}}
+	usedFileNames := map[string]bool{openAPIBaseFileName: true}
+
+	paths := getMapValue(spec.root, "paths")
+	if paths == nil {
+		return namedTemplates, nil
+	}
+
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		apiPath, pathItem := paths.Content[i].Value, spec.resolve(paths.Content[i+1])
+
+		for _, method := range openAPIMethods {
+			operation := getMapValue(pathItem, method)
+			if operation == nil {
+				continue
+			}
+
+			fileName := toFileName(getMapString(operation, "operationId"))
+			if fileName == "" {
+				fileName = toFileName(method + " " + openAPIPathParamRe.ReplaceAllString(apiPath, "$1"))
+			}
+
+			templateDir := ""
+			if tags := getMapValue(operation, "tags"); tags != nil && len(tags.Content) > 0 {
+				templateDir = toFileName(tags.Content[0].Value)
+			}
+
+			templateFileName := path.Join(templateDir, fileName+".ain")
+			for suffix := 2; usedFileNames[templateFileName]; suffix++ {
+				templateFileName = path.Join(templateDir, fmt.Sprintf("%s-%d.ain", fileName, suffix))
+			}
+
+			usedFileNames[templateFileName] = true
+
+			template := spec.getOperationTemplate(method, apiPath, pathItem, operation, globalSecurity)
+
+			includeFileName := openAPIBaseFileName
+			if templateDir != "" {
+				includeFileName = "../" + openAPIBaseFileName
+			}
+
+			template.Include = []string{includeFileName}
+
+			namedTemplates = append(namedTemplates, NamedTemplate{FileName: templateFileName, Template: template})
+		}
+	}
+
+	return namedTemplates, nil
+}
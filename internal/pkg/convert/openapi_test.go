@@ -0,0 +1,188 @@
+package convert
+
+import (
+	"testing"
+)
+
+const testOpenAPISpec = `
+openapi: 3.0.3
+info:
+  title: Petstore
+servers:
+  - url: https://{env}.example.com/v1/
+    variables:
+      env:
+        default: api
+security:
+  - bearerAuth: []
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: "List all pets #1"
+      tags: [Pets]
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            example: 20
+        - name: cursor
+          in: query
+          schema:
+            type: string
+    post:
+      operationId: createPet
+      tags: [Pets]
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        required: true
+        description: The id of the pet
+    delete:
+      tags: [Pets]
+      security:
+        - apiKey: []
+  /health:
+    get: {}
+components:
+  securitySchemes:
+    bearerAuth:
+      type: http
+      scheme: bearer
+    apiKey:
+      type: apiKey
+      in: query
+      name: key
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+          example: Fido
+        age:
+          type: integer
+        tags:
+          type: array
+          items:
+            type: string
+`
+
+func TestFromOpenAPI(t *testing.T) {
+	namedTemplates, err := FromOpenAPI([]byte(testOpenAPISpec))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedTemplates := []struct {
+		fileName string
+		template string
+	}{
+		{"base.ain", `# Petstore
+
+[Host]
+https://api.example.com/v1
+
+[Headers]
+Authorization: Bearer ${BEARER_AUTH}
+
+[Backend]
+curl
+`},
+		{"pets/list-pets.ain", `# List all pets #1
+# Optional parameters: cursor
+
+[Include]
+../base.ain
+
+[Host]
+/pets
+
+[Query]
+limit=20
+`},
+		{"pets/create-pet.ain", `[Include]
+../base.ain
+
+[Host]
+/pets
+
+[Method]
+POST
+
+[Headers]
+Content-Type: application/json
+
+[Body]
+{
+  "name": "Fido",
+  "age": 0,
+  "tags": [
+    "string"
+  ]
+}
+`},
+		{"pets/delete-pets-pet-id.ain", `# ${PET_ID}: The id of the pet
+
+[Include]
+../base.ain
+
+[Host]
+/pets/${PET_ID}
+
+[Query]
+key=${API_KEY}
+
+[Method]
+DELETE
+`},
+		{"get-health.ain", `[Include]
+base.ain
+
+[Host]
+/health
+`},
+	}
+
+	if len(namedTemplates) != len(expectedTemplates) {
+		t.Fatalf("Expected %d templates, Got: %d", len(expectedTemplates), len(namedTemplates))
+	}
+
+	for i, expected := range expectedTemplates {
+		if namedTemplates[i].FileName != expected.fileName {
+			t.Errorf("Expected file name: %s, Got: %s", expected.fileName, namedTemplates[i].FileName)
+		}
+
+		if result := namedTemplates[i].Template.String(); result != expected.template {
+			t.Errorf("Template: %s, Expected:\n%s\nGot:\n%s", expected.fileName, expected.template, result)
+		}
+	}
+}
+
+func TestFromOpenAPINotVersion3(t *testing.T) {
+	_, err := FromOpenAPI([]byte("swagger: '2.0'\n"))
+	if err == nil || err.Error() != "only OpenAPI 3 specs are supported, the spec has no openapi: 3.x version" {
+		t.Errorf("Expected version error, Got: %v", err)
+	}
+}
+
+func TestToEnvVarName(t *testing.T) {
+	tests := map[string]string{
+		"petId":        "PET_ID",
+		"X-Request-Id": "X_REQUEST_ID",
+		"bearer auth":  "BEARER_AUTH",
+	}
+
+	for name, expected := range tests {
+		if result := ToEnvVarName(name); result != expected {
+			t.Errorf("Test: %s, Expected: %s, Got: %s", name, expected, result)
+		}
+	}
+}
@@ -0,0 +1,379 @@
+package convert
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type postmanKeyValue struct {
+	Key      string      `json:"key"`
+	Value    interface{} `json:"value"`
+	Disabled bool        `json:"disabled"`
+	Type     string      `json:"type"`
+}
+
+// Values are mostly strings but
+// numbers and booleans show up too
+func (p postmanKeyValue) getValue() string {
+	switch value := p.Value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	}
+
+	valueJSON, _ := json.Marshal(p.Value)
+	return string(valueJSON)
+}
+
+type postmanAuth struct {
+	Type string `json:"type"`
+	// The parameters per auth type, e g "bearer": [{"key": "token", ...}]
+	Parameters map[string]json.RawMessage `json:"-"`
+}
+
+func (p *postmanAuth) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	if authType, found := fields["type"]; found {
+		if err := json.Unmarshal(authType, &p.Type); err != nil {
+			return err
+		}
+	}
+
+	p.Parameters = fields
+
+	return nil
+}
+
+func (p *postmanAuth) get(key string) string {
+	var parameters []postmanKeyValue
+	// v2.0 collections have the parameters as an object
+	// and are not supported, they are blank here
+	_ = json.Unmarshal(p.Parameters[p.Type], &parameters)
+
+	for _, parameter := range parameters {
+		if parameter.Key == key {
+			return parameter.getValue()
+		}
+	}
+
+	return ""
+}
+
+type postmanEvent struct {
+	Listen string `json:"listen"`
+	Script struct {
+		Exec interface{} `json:"exec"`
+	} `json:"script"`
+}
+
+// hasScript is true if there's any non blank line of script
+func (p postmanEvent) hasScript() bool {
+	switch exec := p.Script.Exec.(type) {
+	case string:
+		return strings.TrimSpace(exec) != ""
+	case []interface{}:
+		for _, line := range exec {
+			if lineStr, ok := line.(string); ok && strings.TrimSpace(lineStr) != "" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+type postmanUrl struct {
+	Raw      string            `json:"raw"`
+	Query    []postmanKeyValue `json:"query"`
+	Variable []postmanKeyValue `json:"variable"`
+}
+
+// The url is either a string or an object
+func (p *postmanUrl) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		p.Raw = raw
+		return nil
+	}
+
+	type postmanUrlObject postmanUrl
+	return json.Unmarshal(data, (*postmanUrlObject)(p))
+}
+
+type postmanBody struct {
+	Mode       string            `json:"mode"`
+	Raw        string            `json:"raw"`
+	Urlencoded []postmanKeyValue `json:"urlencoded"`
+	GraphQL    struct {
+		Query     string `json:"query"`
+		Variables string `json:"variables"`
+	} `json:"graphql"`
+	Options struct {
+		Raw struct {
+			Language string `json:"language"`
+		} `json:"raw"`
+	} `json:"options"`
+	Disabled bool `json:"disabled"`
+}
+
+type postmanRequest struct {
+	Method string            `json:"method"`
+	Header []postmanKeyValue `json:"header"`
+	Url    postmanUrl        `json:"url"`
+	Body   *postmanBody      `json:"body"`
+	Auth   *postmanAuth      `json:"auth"`
+}
+
+// postmanItem is either a folder (with items) or a request
+type postmanItem struct {
+	Name     string            `json:"name"`
+	Item     []postmanItem     `json:"item"`
+	Request  *postmanRequest   `json:"request"`
+	Auth     *postmanAuth      `json:"auth"`
+	Variable []postmanKeyValue `json:"variable"`
+	Event    []postmanEvent    `json:"event"`
+}
+
+type postmanCollection struct {
+	Info struct {
+		Name   string `json:"name"`
+		Schema string `json:"schema"`
+	} `json:"info"`
+	postmanItem
+}
+
+// Path variables in a url, e g /users/:id
+var postmanPathVariableRe = regexp.MustCompile(`/:([a-zA-Z_][a-zA-Z0-9_-]*)`)
+
+func (c *Collection) addPostmanEvents(where string, events []postmanEvent) {
+	for _, event := range events {
+		if event.hasScript() {
+			c.addUntranslated(where, "%s script", event.Listen)
+		}
+	}
+}
+
+func (c *Collection) addPostmanVariables(envFile *NamedEnvFile, where string, variables []postmanKeyValue) {
+	for _, variable := range variables {
+		if variable.Disabled || variable.Key == "" {
+			continue
+		}
+
+		value := variable.getValue()
+		if mustacheVariableRe.MatchString(value) {
+			c.addUntranslated(where, "variable %s refers to other variables, which a .env file cannot", variable.Key)
+		}
+
+		c.addVariable(envFile, where, variable.Key, value)
+	}
+}
+
+func (c *Collection) addPostmanAuth(where string, auth *postmanAuth, template *Template) {
+	switch strings.ToLower(auth.Type) {
+	case "noauth", "":
+	case "bearer":
+		template.Headers = append(template.Headers, "Authorization: Bearer "+c.replaceVariables(where, auth.get("token")))
+	case "basic":
+		username, password := auth.get("username"), auth.get("password")
+		if header, ok := getBasicAuthHeader(username, password); ok {
+			template.Headers = append(template.Headers, header)
+			return
+		}
+
+		template.Auth = "basic " + c.replaceVariables(where, username+":"+password)
+	case "digest":
+		template.Auth = "digest " + c.replaceVariables(where, auth.get("username")+":"+auth.get("password"))
+	case "apikey":
+		key, value := c.replaceVariables(where, auth.get("key")), c.replaceVariables(where, auth.get("value"))
+		if auth.get("in") == "query" {
+			template.Query = append(template.Query, key+"="+value)
+			return
+		}
+
+		template.Headers = append(template.Headers, key+": "+value)
+	case "oauth2":
+		accessToken := auth.get("accessToken")
+		if accessToken == "" {
+			accessToken = "{{accessToken}}"
+			c.addUntranslated(where, "oauth2 has no access token, set ${ACCESS_TOKEN} or use [OAuth2]")
+		}
+
+		template.Headers = append(template.Headers, "Authorization: Bearer "+c.replaceVariables(where, accessToken))
+	default:
+		c.addUntranslated(where, "%s auth", auth.Type)
+	}
+}
+
+func (c *Collection) addPostmanBody(where string, body *postmanBody, template *Template) {
+	if body == nil || body.Disabled {
+		return
+	}
+
+	contentType := ""
+
+	switch body.Mode {
+	case "raw":
+		if body.Raw == "" {
+			return
+		}
+
+		if body.Options.Raw.Language == "json" {
+			contentType = "application/json"
+		}
+
+		template.Body = c.replaceVariablesInLines(where, strings.Split(body.Raw, "\n"))
+	case "urlencoded":
+		formValues := []string{}
+		for _, formValue := range body.Urlencoded {
+			if !formValue.Disabled {
+				formValues = append(formValues, formValue.Key+"="+formValue.getValue())
+			}
+		}
+
+		if len(formValues) == 0 {
+			return
+		}
+
+		contentType = "application/x-www-form-urlencoded"
+		template.Body = []string{c.replaceVariables(where, strings.Join(formValues, "&"))}
+	case "graphql":
+		graphQL := orderedObject{{key: "query", value: body.GraphQL.Query}}
+
+		var variables interface{}
+		if err := json.Unmarshal([]byte(body.GraphQL.Variables), &variables); err == nil {
+			graphQL = append(graphQL, orderedKeyValue{key: "variables", value: variables})
+		}
+
+		graphQLJSON, _ := marshalIndent(graphQL)
+		contentType = "application/json"
+		template.Body = c.replaceVariablesInLines(where, strings.Split(graphQLJSON, "\n"))
+	default:
+		c.addUntranslated(where, "%s body", body.Mode)
+	}
+
+	if contentType == "" {
+		return
+	}
+
+	for _, header := range template.Headers {
+		if strings.HasPrefix(strings.ToLower(header), "content-type:") {
+			return
+		}
+	}
+
+	template.Headers = append(template.Headers, "Content-Type: "+contentType)
+}
+
+func (c *Collection) getPostmanTemplate(where string, request *postmanRequest, auth *postmanAuth, envFile *NamedEnvFile) *Template {
+	template := &Template{}
+
+	host := request.Url.Raw
+	query := []string{}
+
+	if queryStart := strings.Index(host, "?"); queryStart >= 0 {
+		query = strings.Split(host[queryStart+1:], "&")
+		host = host[:queryStart]
+	}
+
+	// The parsed query has the disabled parameters
+	if request.Url.Query != nil {
+		query = []string{}
+		for _, queryParameter := range request.Url.Query {
+			if !queryParameter.Disabled {
+				query = append(query, queryParameter.Key+"="+queryParameter.getValue())
+			}
+		}
+	}
+
+	for _, pathVariable := range request.Url.Variable {
+		c.addVariable(envFile, where, pathVariable.Key, pathVariable.getValue())
+	}
+
+	host = postmanPathVariableRe.ReplaceAllString(host, "/{{$1}}")
+	template.Host = []string{c.replaceVariables(where, host)}
+
+	for _, queryLine := range query {
+		if queryLine != "" {
+			template.Query = append(template.Query, c.replaceVariables(where, queryLine))
+		}
+	}
+
+	if method := strings.ToUpper(request.Method); method != "" && method != "GET" {
+		template.Method = method
+	}
+
+	for _, header := range request.Header {
+		if !header.Disabled {
+			template.Headers = append(template.Headers, c.replaceVariables(where, header.Key+": "+header.getValue()))
+		}
+	}
+
+	if auth != nil {
+		c.addPostmanAuth(where, auth, template)
+	}
+
+	c.addPostmanBody(where, request.Body, template)
+
+	return template
+}
+
+func (c *Collection) addPostmanItems(dirs []string, items []postmanItem, auth *postmanAuth, envFile *NamedEnvFile) {
+	for _, item := range items {
+		where := strings.Join(append(append([]string{}, dirs...), item.Name), "/")
+
+		itemAuth := auth
+		if item.Auth != nil {
+			itemAuth = item.Auth
+		}
+
+		c.addPostmanEvents(where, item.Event)
+
+		if item.Request == nil {
+			c.addPostmanVariables(envFile, where, item.Variable)
+			c.addPostmanItems(append(append([]string{}, dirs...), item.Name), item.Item, itemAuth, envFile)
+			continue
+		}
+
+		if item.Request.Auth != nil {
+			itemAuth = item.Request.Auth
+		}
+
+		template := c.getPostmanTemplate(where, item.Request, itemAuth, envFile)
+		c.addTemplate(dirs, item.Name, template)
+	}
+}
+
+// FromPostman converts a Postman v2.1 collection into a template per
+// request in a directory per folder and the variables into a .env
+func FromPostman(collectionContents []byte) (*Collection, error) {
+	var postmanCollection postmanCollection
+	if err := json.Unmarshal(collectionContents, &postmanCollection); err != nil {
+		return nil, errors.Wrap(err, "could not read the Postman collection")
+	}
+
+	if !strings.Contains(postmanCollection.Info.Schema, "v2.1") {
+		return nil, errors.New("only Postman v2.1 collections are supported, export the collection as Collection v2.1")
+	}
+
+	collection := newCollection(postmanCollection.Info.Name)
+	envFile := NamedEnvFile{FileName: ".env"}
+
+	collectionName := postmanCollection.Info.Name
+	collection.addPostmanEvents(collectionName, postmanCollection.Event)
+	collection.addPostmanVariables(&envFile, collectionName, postmanCollection.Variable)
+	collection.addPostmanItems(nil, postmanCollection.Item, postmanCollection.Auth, &envFile)
+
+	if len(envFile.Variables) > 0 {
+		collection.EnvFiles = append(collection.EnvFiles, envFile)
+	}
+
+	return collection, nil
+}
@@ -0,0 +1,145 @@
+package convert
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testPostmanCollection = `{
+  "info": {"name": "Users API", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+  "auth": {"type": "bearer", "bearer": [{"key": "token", "value": "{{token}}", "type": "string"}]},
+  "variable": [{"key": "baseUrl", "value": "https://api.example.com"}, {"key": "token", "value": "secret token"}],
+  "event": [{"listen": "prerequest", "script": {"exec": ["pm.environment.set('x', 1)"]}}],
+  "item": [
+    {"name": "Users", "variable": [{"key": "limit", "value": 10}], "item": [
+      {"name": "Get user", "request": {"method": "GET",
+        "header": [{"key": "Accept", "value": "application/json"}, {"key": "X-Off", "value": "1", "disabled": true}],
+        "url": {"raw": "{{baseUrl}}/users/:id?limit={{limit}}", "query": [{"key": "limit", "value": "{{limit}}"}, {"key": "x", "value": "1", "disabled": true}], "variable": [{"key": "id", "value": "42"}]}}},
+      {"name": "Create user", "request": {"method": "POST",
+        "auth": {"type": "basic", "basic": [{"key": "username", "value": "ann"}, {"key": "password", "value": "pw"}]},
+        "url": "{{baseUrl}}/users",
+        "body": {"mode": "raw", "raw": "{\n  \"id\": \"{{$guid}}\",\n  \"tag\": \"#1\"\n}", "options": {"raw": {"language": "json"}}}},
+        "event": [{"listen": "test", "script": {"exec": ["pm.test()"]}}]}
+    ]},
+    {"name": "Login", "request": {"method": "POST", "auth": {"type": "noauth"}, "url": "{{baseUrl}}/login",
+      "body": {"mode": "urlencoded", "urlencoded": [{"key": "user", "value": "ann"}, {"key": "pw", "value": "{{password}}"}]}}},
+    {"name": "Login", "request": {"method": "POST", "url": "{{baseUrl}}/upload", "body": {"mode": "formdata", "formdata": []}}}
+  ]
+}`
+
+func TestFromPostman(t *testing.T) {
+	collection, err := FromPostman([]byte(testPostmanCollection))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedTemplates := []struct {
+		fileName string
+		template string
+	}{
+		{"base.ain", `# Users API
+
+[Backend]
+curl
+`},
+		{"users/get-user.ain", `[Include]
+../base.ain
+
+[Host]
+${BASE_URL}/users/${ID}
+
+[Query]
+limit=${LIMIT}
+
+[Headers]
+Accept: application/json
+Authorization: Bearer ${TOKEN}
+`},
+		{"users/create-user.ain", `[Include]
+../base.ain
+
+[Host]
+${BASE_URL}/users
+
+[Method]
+POST
+
+[Headers]
+Authorization: Basic YW5uOnB3
+Content-Type: application/json
+
+[Body]
+{
+  "id": "$(uuidgen)",
+  "tag": "` + "`" + `#1"
+}
+`},
+		{"login.ain", `[Include]
+base.ain
+
+[Host]
+${BASE_URL}/login
+
+[Method]
+POST
+
+[Headers]
+Content-Type: application/x-www-form-urlencoded
+
+[Body]
+user=ann&pw=${PASSWORD}
+`},
+		{"login-2.ain", `[Include]
+base.ain
+
+[Host]
+${BASE_URL}/upload
+
+[Method]
+POST
+
+[Headers]
+Authorization: Bearer ${TOKEN}
+`},
+	}
+
+	if len(collection.Templates) != len(expectedTemplates) {
+		t.Fatalf("Expected %d templates, Got: %d", len(expectedTemplates), len(collection.Templates))
+	}
+
+	for i, expected := range expectedTemplates {
+		if collection.Templates[i].FileName != expected.fileName {
+			t.Errorf("Expected file name: %s, Got: %s", expected.fileName, collection.Templates[i].FileName)
+		}
+
+		if result := collection.Templates[i].Template.String(); result != expected.template {
+			t.Errorf("Template: %s, Expected:\n%s\nGot:\n%s", expected.fileName, expected.template, result)
+		}
+	}
+
+	if len(collection.EnvFiles) != 1 {
+		t.Fatalf("Expected 1 .env file, Got: %d", len(collection.EnvFiles))
+	}
+
+	expectedEnvFile := "BASE_URL=https://api.example.com\nTOKEN=\"secret token\"\nLIMIT=10\nID=42\n"
+	if result := collection.EnvFiles[0].String(); collection.EnvFiles[0].FileName != ".env" || result != expectedEnvFile {
+		t.Errorf("Expected .env:\n%s\nGot %s:\n%s", expectedEnvFile, collection.EnvFiles[0].FileName, result)
+	}
+
+	expectedUntranslated := []string{
+		"Users API: prerequest script",
+		"Users/Create user: test script",
+		"Login: formdata body",
+	}
+
+	if !reflect.DeepEqual(collection.Untranslated, expectedUntranslated) {
+		t.Errorf("Expected untranslated: %v, Got: %v", expectedUntranslated, collection.Untranslated)
+	}
+}
+
+func TestFromPostmanNotVersion21(t *testing.T) {
+	_, err := FromPostman([]byte(`{"info": {"schema": "https://schema.getpostman.com/json/collection/v2.0.0/collection.json"}}`))
+	if err == nil || err.Error() != "only Postman v2.1 collections are supported, export the collection as Collection v2.1" {
+		t.Errorf("Expected version error, Got: %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package convert
+
+import (
+	"strings"
+
+	"github.com/jonaslu/ain/internal/pkg/parse"
+)
+
+// Template is an ain template built by an importer. Values are
+// written as is, so text from outside ain must be escaped first.
+type Template struct {
+	// Written first as # comments, so they need no escaping
+	Comments []string
+
+	Include        []string
+	Host           []string
+	Query          []string
+	Method         string
+	Headers        []string
+	Auth           string
+	Cookies        []string
+	Body           []string
+	Config         []string
+	Backend        string
+	BackendOptions []string
+}
+
+// NamedTemplate is a template and the file name to write it to
+type NamedTemplate struct {
+	FileName string
+	Template *Template
+}
+
+func writeSection(builder *strings.Builder, sectionHeader string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+
+	if builder.Len() > 0 {
+		builder.WriteString("\n")
+	}
+
+	builder.WriteString(sectionHeader + "\n")
+	for _, line := range lines {
+		builder.WriteString(line + "\n")
+	}
+}
+
+func optionalLine(line string) []string {
+	if line == "" {
+		return nil
+	}
+
+	return []string{line}
+}
+
+func (t *Template) String() string {
+	var builder strings.Builder
+
+	for _, comment := range t.Comments {
+		builder.WriteString("# " + strings.Join(strings.Fields(comment), " ") + "\n")
+	}
+
+	writeSection(&builder, "[Include]", t.Include)
+	writeSection(&builder, "[Host]", t.Host)
+	writeSection(&builder, "[Query]", t.Query)
+	writeSection(&builder, "[Method]", optionalLine(t.Method))
+	writeSection(&builder, "[Headers]", t.Headers)
+	writeSection(&builder, "[Auth]", optionalLine(t.Auth))
+	writeSection(&builder, "[Cookies]", t.Cookies)
+	writeSection(&builder, "[Body]", t.Body)
+	writeSection(&builder, "[Config]", t.Config)
+	writeSection(&builder, "[Backend]", optionalLine(t.Backend))
+	writeSection(&builder, "[BackendOptions]", t.BackendOptions)
+
+	return builder.String()
+}
+
+// Escape escapes text that has a meaning to ain (comments,
+// variables and executables) so it's passed on literally
+func Escape(text string) string {
+	text = strings.ReplaceAll(text, "#", "`#")
+	text = strings.ReplaceAll(text, "${", "`${")
+	text = strings.ReplaceAll(text, "$(", "`$(")
+
+	return text
+}
+
+// EscapeLines escapes each line and any line
+// that would otherwise be read as a section header
+func EscapeLines(lines []string) []string {
+	escapedLines := []string{}
+
+	for _, line := range lines {
+		line = Escape(line)
+		if parse.IsSectionHeading(line) {
+			line = "`" + line
+		}
+
+		escapedLines = append(escapedLines, line)
+	}
+
+	return escapedLines
+}
+
+// QuoteBackendOption quotes an option the same way
+// [BackendOptions] are split into arguments
+func QuoteBackendOption(option string) string {
+	if option != "" && !strings.ContainsAny(option, " \t'\"") {
+		return Escape(option)
+	}
+
+	return "'" + strings.ReplaceAll(Escape(option), "'", `\'`) + "'"
+}
@@ -0,0 +1,98 @@
+package data
+
+import (
+	"encoding/base64"
+	"strings"
+)
+
+const (
+	AuthBasic  = "basic"
+	AuthBearer = "bearer"
+	AuthDigest = "digest"
+)
+
+// Replaces secrets when printing the command
+const maskedSecret = "********"
+
+type Auth struct {
+	Scheme string
+
+	// basic and digest
+	Username string
+	Password string
+
+	// bearer
+	Token string
+}
+
+// Masked returns a copy of the auth with the password or token
+// replaced so it can be printed without leaking the secret
+func (a Auth) Masked() Auth {
+	if a.Password != "" {
+		a.Password = maskedSecret
+	}
+
+	if a.Token != "" {
+		a.Token = maskedSecret
+	}
+
+	return a
+}
+
+func (a Auth) UsernameAndPassword() string {
+	return a.Username + ":" + a.Password
+}
+
+func (a Auth) BearerHeader() string {
+	return "Authorization: Bearer " + a.Token
+}
+
+// Header returns the Authorization header the backend sends. Digest
+// has none since it's calculated from the server's challenge.
+func (a Auth) Header() (string, bool) {
+	switch a.Scheme {
+	case AuthBasic:
+		return "Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte(a.UsernameAndPassword())), true
+	case AuthBearer:
+		return a.BearerHeader(), true
+	}
+
+	return "", false
+}
+
+// MaskedHeader returns the Authorization header with only the scheme
+// shown. Digest sends one too, it's just calculated by the backend.
+func (a Auth) MaskedHeader() string {
+	return "Authorization: " + strings.ToUpper(a.Scheme[:1]) + a.Scheme[1:] + " " + maskedSecret
+}
+
+// MaskAuthorizationHeader masks the credentials of an Authorization
+// header (e g from [Sign]) but keeps the scheme, other headers are
+// returned as is
+func MaskAuthorizationHeader(header string) string {
+	headerName, headerValue, found := strings.Cut(header, ":")
+	if !found || !strings.EqualFold(strings.TrimSpace(headerName), "Authorization") {
+		return header
+	}
+
+	scheme := strings.Fields(headerValue)
+	if len(scheme) == 0 {
+		return header
+	}
+
+	return headerName + ": " + scheme[0] + " " + maskedSecret
+}
+
+// The header [Sign] adds for aws-sigv4 with temporary credentials
+const sessionTokenHeaderName = "X-Amz-Security-Token"
+
+// MaskSessionTokenHeader masks the value of an aws-sigv4 session
+// token header, other headers are returned as is
+func MaskSessionTokenHeader(header string) string {
+	headerName, _, found := strings.Cut(header, ":")
+	if !found || !strings.EqualFold(strings.TrimSpace(headerName), sessionTokenHeaderName) {
+		return header
+	}
+
+	return headerName + ": " + maskedSecret
+}
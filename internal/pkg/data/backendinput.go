@@ -1,14 +1,41 @@
 package data
 
 import (
+	"bufio"
+	"io"
 	"os"
 	"strings"
 
 	"github.com/pkg/errors"
 )
 
+// GetBody returns the body as it's passed to the backend
+func (bi *BackendInput) GetBody() string {
+	return strings.Join(bi.Body, "\n")
+}
+
+// GetMethod returns the method the backend will use. All
+// backends default to POST when there's a body and GET otherwise.
+func (bi *BackendInput) GetMethod() string {
+	if bi.Method != "" {
+		return strings.ToUpper(bi.Method)
+	}
+
+	if len(bi.Body) > 0 || bi.BodyFromStdin {
+		return "POST"
+	}
+
+	return "GET"
+}
+
+// PrintsInlineBody is true when the body is part of the printed
+// command rather than written to a file it refers to
+func (bi *BackendInput) PrintsInlineBody() bool {
+	return bi.PrintCommand && bi.InlineBody && len(bi.Body) > 0
+}
+
 func (bi *BackendInput) CreateBodyTempFile() error {
-	if len(bi.Body) == 0 {
+	if len(bi.Body) == 0 || bi.PrintsInlineBody() {
 		return nil
 	}
 
@@ -23,7 +50,7 @@ func (bi *BackendInput) CreateBodyTempFile() error {
 		tempFileDir = cwd
 	}
 
-	bodyStr := strings.Join(bi.Body, "\n")
+	bodyStr := bi.GetBody()
 
 	tmpFile, err := os.CreateTemp(tempFileDir, "ain-body")
 	if err != nil {
@@ -43,6 +70,56 @@ func (bi *BackendInput) CreateBodyTempFile() error {
 	return nil
 }
 
+// ReadBodyFromStdin buffers the piped stdin into the body for
+// when it cannot be streamed to the backend
+func (bi *BackendInput) ReadBodyFromStdin() error {
+	return bi.readBody(os.Stdin)
+}
+
+func (bi *BackendInput) readBody(reader io.Reader) error {
+	bodyBytes, err := io.ReadAll(reader)
+	if err != nil {
+		return errors.Wrap(err, "could not read body from pipe stdin")
+	}
+
+	bi.BodyFromStdin = false
+
+	// An empty pipe is no body, same as an empty [Body]
+	if len(bodyBytes) == 0 {
+		bi.Body = nil
+		return nil
+	}
+
+	bi.Body = strings.Split(string(bodyBytes), "\n")
+
+	return nil
+}
+
+// PeekBodyFromStdin checks that the piped stdin has a body before it's
+// streamed to the backend. An empty pipe is no body, same as when buffered.
+func (bi *BackendInput) PeekBodyFromStdin() error {
+	return bi.peekBody(os.Stdin)
+}
+
+func (bi *BackendInput) peekBody(reader io.Reader) error {
+	bodyReader := bufio.NewReader(reader)
+	if _, err := bodyReader.Peek(1); err != nil {
+		if err != io.EOF {
+			return errors.Wrap(err, "could not read body from pipe stdin")
+		}
+
+		bi.BodyFromStdin = false
+		bi.Body = nil
+
+		return nil
+	}
+
+	// The peeked byte is still in the reader
+	bi.StdinBody = bodyReader
+
+	return nil
+}
+
 func (bi *BackendInput) RemoveBodyTempFile(forceDeletion bool) error {
 	if bi.TempFileName == "" {
 		return nil
@@ -0,0 +1,79 @@
+package data
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBackendInput_readBody(t *testing.T) {
+	tests := map[string]struct {
+		stdin          string
+		expectedBody   []string
+		expectedMethod string
+	}{
+		"Body":        {stdin: "{\n}", expectedBody: []string{"{", "}"}, expectedMethod: "POST"},
+		"Empty pipe":  {stdin: "", expectedBody: nil, expectedMethod: "GET"},
+		"Only a line": {stdin: "\n", expectedBody: []string{"", ""}, expectedMethod: "POST"},
+	}
+
+	for name, test := range tests {
+		backendInput := BackendInput{Body: []string{"from template"}, BodyFromStdin: true}
+		if err := backendInput.readBody(strings.NewReader(test.stdin)); err != nil {
+			t.Errorf("Test: %s, Unexpected error: %v", name, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(backendInput.Body, test.expectedBody) {
+			t.Errorf("Test: %s, Expected: %q, Got: %q", name, test.expectedBody, backendInput.Body)
+		}
+
+		if method := backendInput.GetMethod(); method != test.expectedMethod {
+			t.Errorf("Test: %s, Expected: %s, Got: %s", name, test.expectedMethod, method)
+		}
+
+		if err := backendInput.CreateBodyTempFile(); err != nil || (test.expectedBody == nil && backendInput.TempFileName != "") {
+			t.Errorf("Test: %s, Expected: no temp-file, Got: %s %v", name, backendInput.TempFileName, err)
+		}
+
+		_ = backendInput.RemoveBodyTempFile(true)
+	}
+}
+
+func TestBackendInput_peekBody(t *testing.T) {
+	tests := map[string]struct {
+		stdin             string
+		expectedFromStdin bool
+		expectedMethod    string
+	}{
+		"Body":       {stdin: "{\n}", expectedFromStdin: true, expectedMethod: "POST"},
+		"Empty pipe": {stdin: "", expectedFromStdin: false, expectedMethod: "GET"},
+	}
+
+	for name, test := range tests {
+		backendInput := BackendInput{BodyFromStdin: true}
+		if err := backendInput.peekBody(strings.NewReader(test.stdin)); err != nil {
+			t.Errorf("Test: %s, Unexpected error: %v", name, err)
+			continue
+		}
+
+		if backendInput.BodyFromStdin != test.expectedFromStdin {
+			t.Errorf("Test: %s, Expected: %v, Got: %v", name, test.expectedFromStdin, backendInput.BodyFromStdin)
+		}
+
+		if method := backendInput.GetMethod(); method != test.expectedMethod {
+			t.Errorf("Test: %s, Expected: %s, Got: %s", name, test.expectedMethod, method)
+		}
+
+		if !test.expectedFromStdin {
+			continue
+		}
+
+		// Nothing peeked is lost from the streamed body
+		streamedBody, _ := io.ReadAll(backendInput.StdinBody)
+		if string(streamedBody) != test.stdin {
+			t.Errorf("Test: %s, Expected: %q, Got: %q", name, test.stdin, streamedBody)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package data
+
+// Exit codes for --status-exit, the same whatever the backend
+const (
+	StatusExitOk          = 0
+	StatusExitNoResponse  = 3
+	StatusExitClientError = 4
+	StatusExitServerError = 5
+)
+
+// StatusExitCode maps the response status to an exit code. If the
+// status could not be read the backend's exit code tells if there
+// was a response.
+func (bo *BackendOutput) StatusExitCode() int {
+	switch {
+	case bo.StatusCode >= 500:
+		return StatusExitServerError
+	case bo.StatusCode >= 400:
+		return StatusExitClientError
+	case bo.StatusCode > 0 || bo.ExitCode == 0:
+		return StatusExitOk
+	}
+
+	return StatusExitNoResponse
+}
@@ -0,0 +1,24 @@
+package data
+
+import "testing"
+
+func TestBackendOutput_StatusExitCode(t *testing.T) {
+	tests := map[string]struct {
+		backendOutput    BackendOutput
+		expectedExitCode int
+	}{
+		"2xx":                     {BackendOutput{StatusCode: 201, ExitCode: 0}, StatusExitOk},
+		"3xx":                     {BackendOutput{StatusCode: 304, ExitCode: 0}, StatusExitOk},
+		"4xx":                     {BackendOutput{StatusCode: 404, ExitCode: 0}, StatusExitClientError},
+		"4xx wget":                {BackendOutput{StatusCode: 404, ExitCode: 8}, StatusExitClientError},
+		"5xx":                     {BackendOutput{StatusCode: 503, ExitCode: 0}, StatusExitServerError},
+		"No response":             {BackendOutput{ExitCode: 7}, StatusExitNoResponse},
+		"Status not read, exit 0": {BackendOutput{ExitCode: 0}, StatusExitOk},
+	}
+
+	for name, test := range tests {
+		if exitCode := test.backendOutput.StatusExitCode(); exitCode != test.expectedExitCode {
+			t.Errorf("Test: %s, Expected: %d, Got: %d", name, test.expectedExitCode, exitCode)
+		}
+	}
+}
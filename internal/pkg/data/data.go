@@ -1,7 +1,9 @@
 package data
 
 import (
+	"io"
 	"net/url"
+	"time"
 )
 
 const TimeoutNotSet = -1
@@ -9,6 +11,42 @@ const TimeoutNotSet = -1
 type Config struct {
 	Timeout    int32
 	QueryDelim *string
+	CookieJar  *string
+
+	Proxy      *string
+	CACert     *string
+	ClientCert *string
+	ClientKey  *string
+	Insecure   *bool
+
+	FollowRedirects *bool
+	MaxRedirects    *int32
+	Verbose         *bool
+	Compressed      *bool
+	HTTPVersion     *string
+	ConnectTimeout  *int32
+
+	UnixSocket *string
+
+	Retries        *int32
+	RetryBackoff   *int32
+	RetryStatus    []int
+	RetryExitCodes []int
+}
+
+// IsTrue is for config that's on or off, where not set is off
+func IsTrue(value *bool) bool {
+	return value != nil && *value
+}
+
+// FollowsRedirects is FollowRedirects if set. Otherwise
+// giving MaxRedirects means the redirects are followed.
+func (config Config) FollowsRedirects() bool {
+	if config.FollowRedirects != nil {
+		return *config.FollowRedirects
+	}
+
+	return config.MaxRedirects != nil
 }
 
 func NewConfig() Config {
@@ -20,12 +58,31 @@ type BackendInput struct {
 	Body    []string
 	Method  string
 	Headers []string
+	Auth    *Auth
+	OAuth2  *OAuth2
+	Sign    *Sign
 
 	Backend        string
 	BackendOptions [][]string
+	// The [Backend] the [BackendOptions] are written
+	// for when it's replaced with ain --as
+	TemplateBackend string
+	// [BackendOptions] with no counterpart
+	// in the backend given with ain --as
+	LeftOutBackendOptions []string
+	Config                Config
 
-	PrintCommand  bool
+	PrintCommand bool
+	InlineBody   bool
+	// The shell the printed command is quoted for
+	PrintShell    string
 	LeaveTempFile bool
+	BodyFromStdin bool
+	// The piped body when it's streamed to the backend
+	StdinBody io.Reader
+	// Makes the backend print the response head so
+	// the status and headers can be read
+	InspectResponse bool
 
 	TempFileName string
 }
@@ -36,4 +93,13 @@ type BackendOutput struct {
 	Stderr   string
 	Stdout   string
 	ExitCode int
+
+	// Set when the response is inspected, StatusCode
+	// is 0 if the backend got no response
+	StatusCode int
+	Status     string
+	Headers    []string
+
+	// How long the (last) backend call took
+	Duration time.Duration
 }
@@ -0,0 +1,17 @@
+package data
+
+const (
+	OAuth2ClientCredentials = "client_credentials"
+	OAuth2RefreshToken      = "refresh_token"
+)
+
+// OAuth2 is the config to fetch an access token
+// that's then passed as bearer [Auth]
+type OAuth2 struct {
+	TokenUrl     string
+	ClientId     string
+	ClientSecret string
+	Scope        string
+	GrantType    string
+	RefreshToken string
+}
@@ -0,0 +1,24 @@
+package data
+
+const (
+	SignAWSSigV4 = "aws-sigv4"
+	SignHMAC     = "hmac"
+)
+
+// Sign is the config to sign the assembled request,
+// the signature is added as headers before calling the backend
+type Sign struct {
+	Type string
+
+	// aws-sigv4
+	Service string
+	Region  string
+
+	// hmac
+	Key       string
+	Algorithm string
+	Header    string
+	Prefix    string
+	Encoding  string
+	Parts     []string
+}
@@ -0,0 +1,137 @@
+package disk
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Base template file names in the order they are assembled
+// when found in the same directory
+var baseTemplateFilenames = []string{".ain-defaults", "_base.ain"}
+
+// The search for base templates stops at the first directory
+// containing any of these
+var projectRootMarkers = []string{".ain-root", ".git"}
+
+func fileExists(filename string) bool {
+	_, err := os.Stat(filename)
+	return err == nil
+}
+
+func isProjectRoot(dir string) bool {
+	for _, projectRootMarker := range projectRootMarkers {
+		if fileExists(filepath.Join(dir, projectRootMarker)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isSearchBoundary is true for the home dir and the file system
+// root when they aren't the project root. Base templates there
+// belong to no project and are never picked up.
+func isSearchBoundary(dir, homeDir string) bool {
+	return !isProjectRoot(dir) && (dir == homeDir || filepath.Dir(dir) == dir)
+}
+
+// getDirBaseTemplates returns the base templates from the project root
+// (or below the home dir or file system root if no marker is found)
+// down to dir
+func getDirBaseTemplates(dir, homeDir string) []string {
+	dirBaseTemplates := [][]string{}
+
+	for {
+		baseTemplates := []string{}
+		for _, baseTemplateFilename := range baseTemplateFilenames {
+			if baseTemplate := filepath.Join(dir, baseTemplateFilename); fileExists(baseTemplate) {
+				baseTemplates = append(baseTemplates, baseTemplate)
+			}
+		}
+
+		dirBaseTemplates = append([][]string{baseTemplates}, dirBaseTemplates...)
+
+		parentDir := filepath.Dir(dir)
+		if isProjectRoot(dir) || parentDir == dir || isSearchBoundary(parentDir, homeDir) {
+			break
+		}
+
+		dir = parentDir
+	}
+
+	result := []string{}
+	for _, baseTemplates := range dirBaseTemplates {
+		result = append(result, baseTemplates...)
+	}
+
+	return result
+}
+
+func getDisplayFilename(cwd, absoluteFilename string) string {
+	relativeFilename, err := filepath.Rel(cwd, absoluteFilename)
+	if err != nil {
+		return absoluteFilename
+	}
+
+	return relativeFilename
+}
+
+// getSeenTemplateName is the template file without any #request
+// or ! so the same file is only seen once
+func getSeenTemplateName(templateFileName string) string {
+	templateFileName, _ = SplitRequestName(strings.TrimSuffix(templateFileName, EditFileSuffix))
+	if absoluteTemplateFileName, err := filepath.Abs(templateFileName); err == nil {
+		return absoluteTemplateFileName
+	}
+
+	return templateFileName
+}
+
+// AddBaseTemplateFilenames walks up from the directory of each template
+// and puts any base templates found before it, outermost first. Base
+// templates already given as template file names, or found for an
+// earlier template, are left out.
+func AddBaseTemplateFilenames(templateFileNames []string) ([]string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get current working dir, cannot discover base templates")
+	}
+
+	// No home dir only leaves the file system root as the boundary
+	homeDir, _ := os.UserHomeDir()
+	if homeDir != "" {
+		homeDir = filepath.Clean(homeDir)
+	}
+
+	seenTemplates := map[string]bool{}
+	for _, templateFileName := range templateFileNames {
+		if !IsStdinTemplateName(templateFileName) {
+			seenTemplates[getSeenTemplateName(templateFileName)] = true
+		}
+	}
+
+	withBaseTemplateFilenames := []string{}
+
+	for _, templateFileName := range templateFileNames {
+		templateDir := cwd
+		if !IsStdinTemplateName(templateFileName) {
+			templateDir = filepath.Dir(getSeenTemplateName(templateFileName))
+		}
+
+		for _, baseTemplate := range getDirBaseTemplates(templateDir, homeDir) {
+			if seenTemplates[baseTemplate] {
+				continue
+			}
+
+			seenTemplates[baseTemplate] = true
+			withBaseTemplateFilenames = append(withBaseTemplateFilenames, getDisplayFilename(cwd, baseTemplate))
+		}
+
+		withBaseTemplateFilenames = append(withBaseTemplateFilenames, templateFileName)
+	}
+
+	return withBaseTemplateFilenames, nil
+}
@@ -0,0 +1,52 @@
+package disk
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_getDirBaseTemplates(t *testing.T) {
+	tests := map[string]struct {
+		files    []string
+		expected []string
+	}{
+		"No marker stops below home": {
+			files:    []string{"_base.ain", "proj/_base.ain", "proj/api/_base.ain"},
+			expected: []string{"proj/_base.ain", "proj/api/_base.ain"},
+		},
+		"Marker stops at the project root": {
+			files:    []string{"_base.ain", "proj/_base.ain", "proj/.ain-root", "proj/api/.ain-defaults", "proj/api/_base.ain"},
+			expected: []string{"proj/_base.ain", "proj/api/.ain-defaults", "proj/api/_base.ain"},
+		},
+		"Marker in home": {
+			files:    []string{".ain-root", "_base.ain", "proj/api/_base.ain"},
+			expected: []string{"_base.ain", "proj/api/_base.ain"},
+		},
+	}
+
+	for name, test := range tests {
+		homeDir := t.TempDir()
+
+		templateDir := filepath.Join(homeDir, "proj", "api")
+		if err := os.MkdirAll(templateDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, file := range test.files {
+			if err := os.WriteFile(filepath.Join(homeDir, file), nil, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		expected := []string{}
+		for _, file := range test.expected {
+			expected = append(expected, filepath.Join(homeDir, file))
+		}
+
+		if result := getDirBaseTemplates(templateDir, homeDir); !reflect.DeepEqual(result, expected) {
+			t.Errorf("Test: %s, Expected: %v, Got: %v", name, expected, result)
+		}
+	}
+}
@@ -3,18 +3,90 @@ package disk
 import (
 	"io"
 	"os"
+	"strings"
 
 	"github.com/jonaslu/ain/internal/pkg/utils"
 	"github.com/pkg/errors"
 )
 
-func GetTemplateFilenames(cmdParamTemplateFileNames []string) ([]string, error) {
+func StdinIsPipe() (bool, error) {
 	fi, err := os.Stdin.Stat()
 	if err != nil {
-		return nil, errors.Wrap(err, "could not stat stdin")
+		return false, errors.Wrap(err, "could not stat stdin")
+	}
+
+	return (fi.Mode() & os.ModeCharDevice) == 0, nil
+}
+
+// StdinTemplateName as a template file name reads the
+// template contents from stdin instead of a file
+const StdinTemplateName = "-"
+
+func IsStdinTemplateName(templateFileName string) bool {
+	templateFileName, _ = SplitRequestName(strings.TrimSuffix(templateFileName, EditFileSuffix))
+	return templateFileName == StdinTemplateName
+}
+
+// RequestNameSeparator selects one named request
+// in a template file (e g file.ain#get-user)
+const RequestNameSeparator = "#"
+
+// SplitRequestName returns the template file name and any
+// request name selected. Files actually named with the
+// separator are left as is.
+func SplitRequestName(templateFileName string) (string, string) {
+	separatorIndex := strings.LastIndex(templateFileName, RequestNameSeparator)
+	if separatorIndex < 0 || fileExists(templateFileName) {
+		return templateFileName, ""
+	}
+
+	return templateFileName[:separatorIndex], templateFileName[separatorIndex+1:]
+}
+
+func hasStdinTemplateName(templateFileNames []string) (bool, error) {
+	found := false
+
+	for _, templateFileName := range templateFileNames {
+		if !IsStdinTemplateName(templateFileName) {
+			continue
+		}
+
+		if found {
+			return true, errors.New("template from stdin (-) can only be given once")
+		}
+
+		// The pipe is read to its end before the editor would open
+		if strings.HasSuffix(templateFileName, EditFileSuffix) {
+			return true, errors.New("template from stdin (-) cannot be opened in an editor (!), write it to a file first")
+		}
+
+		found = true
+	}
+
+	return found, nil
+}
+
+func GetTemplateFilenames(cmdParamTemplateFileNames []string) ([]string, error) {
+	stdinIsPipe, err := StdinIsPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	hasStdinTemplate, err := hasStdinTemplateName(cmdParamTemplateFileNames)
+	if err != nil {
+		return nil, err
+	}
+
+	if hasStdinTemplate {
+		if !stdinIsPipe {
+			return nil, errors.New("template from stdin (-) given but ain is not connected to a pipe")
+		}
+
+		// Stdin is the template contents, not names
+		return cmdParamTemplateFileNames, nil
 	}
 
-	if (fi.Mode() & os.ModeCharDevice) == 0 {
+	if stdinIsPipe {
 		fileNameBytes, err := io.ReadAll(os.Stdin)
 		if err != nil {
 			return nil, errors.Wrap(err, "could not read pipe stdin")
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/jonaslu/ain/internal/pkg/call"
@@ -122,3 +123,31 @@ func GenerateEmptyTemplates(templateFileNames []string) error {
 
 	return nil
 }
+
+// WriteTemplates writes imported templates (and .env files), creating any directories.
+// Nothing is written if any of the files already exists.
+func WriteTemplates(filenames, contents []string) error {
+	for _, filename := range filenames {
+		if _, err := os.Stat(filename); !os.IsNotExist(err) {
+			return errors.Errorf("cannot write template. File already exists %s", filename)
+		}
+	}
+
+	for i, filename := range filenames {
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			return errors.Wrapf(err, "could not create directory for template %s", filename)
+		}
+
+		// .env files hold the credentials from the imported environments
+		fileMode := os.FileMode(0644)
+		if strings.HasPrefix(filepath.Base(filename), ".env") {
+			fileMode = 0600
+		}
+
+		if err := os.WriteFile(filename, []byte(contents[i]), fileMode); err != nil {
+			return errors.Wrapf(err, "could not write template to file %s", filename)
+		}
+	}
+
+	return nil
+}
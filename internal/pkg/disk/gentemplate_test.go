@@ -0,0 +1,41 @@
+package disk
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteTemplates(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := map[string]os.FileMode{
+		"api/get-user.ain":   0644,
+		".env":               0600,
+		".env.production":    0600,
+		"api/.env.staging":   0600,
+		"api/environment.md": 0644,
+	}
+
+	filenames, contents := []string{}, []string{}
+	for filename := range tests {
+		filenames = append(filenames, filepath.Join(dir, filename))
+		contents = append(contents, "")
+	}
+
+	if err := WriteTemplates(filenames, contents); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for filename, expectedMode := range tests {
+		fileInfo, err := os.Stat(filepath.Join(dir, filename))
+		if err != nil {
+			t.Errorf("Test: %s, Unexpected error: %v", filename, err)
+			continue
+		}
+
+		if mode := fileInfo.Mode().Perm(); mode&^expectedMode != 0 || mode&0600 != 0600 {
+			t.Errorf("Test: %s, Expected: %v, Got: %v", filename, expectedMode, mode)
+		}
+	}
+}
@@ -11,6 +11,9 @@ import (
 
 const fallbackEditor = "vim"
 
+// EditFileSuffix appended to a template file name opens it in an editor
+const EditFileSuffix = "!"
+
 func captureEditorOutput(tempFile *os.File) (string, error) {
 	editorEnvVarName := "VISUAL"
 	editorEnvStr := os.Getenv(editorEnvVarName)
@@ -71,6 +74,8 @@ func readEditedRawTemplateString(sourceTemplateFileName string) (string, error)
 		return "", errors.Wrapf(err, "cannot open source template file %s", sourceTemplateFileName)
 	}
 
+	defer rawTemplateString.Close()
+
 	// .ini formats it like ini file in some editors
 	tempFile, err := os.CreateTemp("", "ain*.ini")
 	if err != nil {
@@ -102,6 +107,15 @@ func ReadRawTemplateString(templateFileName string, editFile bool) (string, erro
 		return readEditedRawTemplateString(templateFileName)
 	}
 
+	if templateFileName == StdinTemplateName {
+		stdinContents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", errors.Wrap(err, "could not read template from pipe stdin")
+		}
+
+		return string(stdinContents), nil
+	}
+
 	fileContents, err := os.ReadFile(templateFileName)
 	if err != nil {
 		return "", errors.Wrapf(err, "could not read template file %s", templateFileName)
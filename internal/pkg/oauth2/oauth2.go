@@ -0,0 +1,257 @@
+package oauth2
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+	"github.com/pkg/errors"
+)
+
+// A cached token is refetched when it's this close to expiring
+// so it doesn't expire while the backend is running
+const expiryMargin = 30 * time.Second
+
+// PrintedAccessToken stands in for the access token when the
+// request is only printed, printed secrets are masked anyway
+const PrintedAccessToken = "<access token>"
+
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	RefreshToken     string `json:"refresh_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+type cachedToken struct {
+	AccessToken string    `json:"access_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	// A token server can hand out a new refresh token with the access
+	// token and revoke the old one, this is the latest handed out
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+func (token cachedToken) hasExpired() bool {
+	return token.AccessToken == "" || time.Now().Add(expiryMargin).After(token.ExpiresAt)
+}
+
+func getCacheFilename(config data.OAuth2) (string, error) {
+	userCacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	// The secrets are part of the key so a changed
+	// secret never gets a token fetched with the old one
+	configHash := sha256.Sum256([]byte(strings.Join([]string{
+		config.TokenUrl,
+		config.ClientId,
+		config.ClientSecret,
+		config.Scope,
+		config.GrantType,
+		config.RefreshToken,
+	}, "\n")))
+
+	return filepath.Join(userCacheDir, "ain", "oauth2", hex.EncodeToString(configHash[:])+".json"), nil
+}
+
+func readCachedToken(cacheFilename string) cachedToken {
+	var token cachedToken
+
+	cachedTokenBytes, err := os.ReadFile(cacheFilename)
+	if err != nil {
+		return token
+	}
+
+	if err := json.Unmarshal(cachedTokenBytes, &token); err != nil {
+		return cachedToken{}
+	}
+
+	return token
+}
+
+func writeCachedToken(cacheFilename string, token cachedToken) error {
+	if err := os.MkdirAll(filepath.Dir(cacheFilename), 0700); err != nil {
+		return err
+	}
+
+	cachedTokenBytes, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(cacheFilename, cachedTokenBytes, 0600)
+}
+
+func getTokenRequestForm(config data.OAuth2) url.Values {
+	form := url.Values{}
+	form.Set("grant_type", config.GrantType)
+
+	if config.Scope != "" {
+		form.Set("scope", config.Scope)
+	}
+
+	if config.GrantType == data.OAuth2RefreshToken {
+		form.Set("refresh_token", config.RefreshToken)
+	}
+
+	return form
+}
+
+// getTokenClient calls the token url with the same [Config] as the
+// backend calls the api, the Timeout is already on the context
+func getTokenClient(config data.Config) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: data.IsTrue(config.Insecure)}
+
+	if config.Proxy != nil {
+		// Curl defaults to http when the proxy has no scheme
+		proxy := *config.Proxy
+		if !strings.Contains(proxy, "://") {
+			proxy = "http://" + proxy
+		}
+
+		proxyUrl, err := url.Parse(proxy)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not parse [Config] Proxy=%s", *config.Proxy)
+		}
+
+		transport.Proxy = http.ProxyURL(proxyUrl)
+	}
+
+	if config.CACert != nil {
+		caCertBytes, err := os.ReadFile(*config.CACert)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read [Config] CACert=%s", *config.CACert)
+		}
+
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM(caCertBytes) {
+			return nil, errors.Errorf("no certificates found in [Config] CACert=%s", *config.CACert)
+		}
+
+		transport.TLSClientConfig.RootCAs = caCertPool
+	}
+
+	if config.ClientCert != nil {
+		// Without a ClientKey the key is in the certificate file
+		clientKey := *config.ClientCert
+		if config.ClientKey != nil {
+			clientKey = *config.ClientKey
+		}
+
+		clientCert, err := tls.LoadX509KeyPair(*config.ClientCert, clientKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not load [Config] ClientCert=%s", *config.ClientCert)
+		}
+
+		transport.TLSClientConfig.Certificates = []tls.Certificate{clientCert}
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
+
+func fetchToken(ctx context.Context, config data.OAuth2, client *http.Client) (tokenResponse, error) {
+	var token tokenResponse
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.TokenUrl, strings.NewReader(getTokenRequestForm(config).Encode()))
+	if err != nil {
+		return token, errors.Wrapf(err, "could not create oauth2 token request to %s", config.TokenUrl)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(config.ClientId), url.QueryEscape(config.ClientSecret))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return token, errors.Wrapf(err, "could not fetch oauth2 token from %s", config.TokenUrl)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return token, errors.Wrapf(err, "could not read oauth2 token response from %s", config.TokenUrl)
+	}
+
+	if err := json.Unmarshal(respBody, &token); err != nil && resp.StatusCode == http.StatusOK {
+		return token, errors.Wrapf(err, "could not parse oauth2 token response from %s", config.TokenUrl)
+	}
+
+	if resp.StatusCode != http.StatusOK || token.Error != "" {
+		if token.Error != "" {
+			return token, errors.Errorf("oauth2 token request to %s failed with %s: %s %s", config.TokenUrl, resp.Status, token.Error, token.ErrorDescription)
+		}
+
+		return token, errors.Errorf("oauth2 token request to %s failed with %s: %s", config.TokenUrl, resp.Status, strings.TrimSpace(string(respBody)))
+	}
+
+	if token.AccessToken == "" {
+		return token, errors.Errorf("oauth2 token response from %s has no access_token", config.TokenUrl)
+	}
+
+	return token, nil
+}
+
+// GetAccessToken returns a token fetched earlier if it hasn't expired.
+// Otherwise a new token is fetched from the token url and cached in
+// the users cache dir until it expires. The token url is called with
+// the proxy, certificates and timeout set in [Config].
+func GetAccessToken(ctx context.Context, config data.OAuth2, backendConfig data.Config) (string, error) {
+	var cached cachedToken
+
+	cacheFilename, cacheErr := getCacheFilename(config)
+	if cacheErr == nil {
+		if cached = readCachedToken(cacheFilename); !cached.hasExpired() {
+			return cached.AccessToken, nil
+		}
+	}
+
+	if config.GrantType == data.OAuth2RefreshToken && cached.RefreshToken != "" {
+		config.RefreshToken = cached.RefreshToken
+	}
+
+	client, err := getTokenClient(backendConfig)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := fetchToken(ctx, config, client)
+	if err != nil {
+		return "", err
+	}
+
+	refreshToken := cached.RefreshToken
+	if token.RefreshToken != "" {
+		refreshToken = token.RefreshToken
+	}
+
+	// Tokens without expiry are fetched every time,
+	// but any new refresh token is still kept
+	if cacheErr == nil && (token.ExpiresIn > 0 || refreshToken != "") {
+		newCachedToken := cachedToken{RefreshToken: refreshToken}
+		if token.ExpiresIn > 0 {
+			newCachedToken.AccessToken = token.AccessToken
+			newCachedToken.ExpiresAt = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+		}
+
+		if err := writeCachedToken(cacheFilename, newCachedToken); err != nil {
+			return "", errors.Wrapf(err, "could not cache oauth2 token in %s", cacheFilename)
+		}
+	}
+
+	return token.AccessToken, nil
+}
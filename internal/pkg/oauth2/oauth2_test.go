@@ -0,0 +1,197 @@
+package oauth2
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+)
+
+func newTokenServer(t *testing.T, tokenRequests *int, response string, status int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*tokenRequests++
+
+		clientId, clientSecret, ok := r.BasicAuth()
+		if !ok || clientId != "client" || clientSecret != "secret" {
+			t.Errorf("Expected client credentials as basic auth, got: %s %s", clientId, clientSecret)
+		}
+
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+
+		if r.PostForm.Get("grant_type") == data.OAuth2RefreshToken && r.PostForm.Get("refresh_token") != "refresh" {
+			t.Errorf("Expected refresh token in form, got: %s", r.PostForm.Encode())
+		}
+
+		w.WriteHeader(status)
+		fmt.Fprint(w, response)
+	}))
+}
+
+func Test_GetAccessToken(t *testing.T) {
+	tests := map[string]struct {
+		grantType             string
+		response              string
+		status                int
+		expectedAccessToken   string
+		expectedTokenRequests int
+		expectedErrorContains string
+	}{
+		"Token with expiry is cached": {
+			grantType:             data.OAuth2ClientCredentials,
+			response:              `{"access_token":"abc","token_type":"Bearer","expires_in":3600}`,
+			status:                http.StatusOK,
+			expectedAccessToken:   "abc",
+			expectedTokenRequests: 1,
+		},
+		"Token without expiry is fetched every time": {
+			grantType:             data.OAuth2RefreshToken,
+			response:              `{"access_token":"def","token_type":"Bearer"}`,
+			status:                http.StatusOK,
+			expectedAccessToken:   "def",
+			expectedTokenRequests: 2,
+		},
+		"Token about to expire is fetched every time": {
+			grantType:             data.OAuth2ClientCredentials,
+			response:              `{"access_token":"ghi","expires_in":10}`,
+			status:                http.StatusOK,
+			expectedAccessToken:   "ghi",
+			expectedTokenRequests: 2,
+		},
+		"Error response is returned": {
+			grantType:             data.OAuth2ClientCredentials,
+			response:              `{"error":"invalid_client","error_description":"bad secret"}`,
+			status:                http.StatusUnauthorized,
+			expectedTokenRequests: 2,
+			expectedErrorContains: "invalid_client bad secret",
+		},
+	}
+
+	for name, test := range tests {
+		t.Setenv("XDG_CACHE_HOME", t.TempDir())
+		t.Setenv("HOME", t.TempDir())
+
+		tokenRequests := 0
+		tokenServer := newTokenServer(t, &tokenRequests, test.response, test.status)
+
+		config := data.OAuth2{
+			TokenUrl:     tokenServer.URL,
+			ClientId:     "client",
+			ClientSecret: "secret",
+			GrantType:    test.grantType,
+			RefreshToken: "refresh",
+		}
+
+		for i := 0; i < 2; i++ {
+			accessToken, err := GetAccessToken(context.Background(), config, data.NewConfig())
+
+			if test.expectedErrorContains != "" {
+				if err == nil || !strings.Contains(err.Error(), test.expectedErrorContains) {
+					t.Errorf("Test: %s. Expected error containing %s, got: %v", name, test.expectedErrorContains, err)
+				}
+
+				continue
+			}
+
+			if err != nil {
+				t.Errorf("Test: %s. Unexpected error: %v", name, err)
+			}
+
+			if accessToken != test.expectedAccessToken {
+				t.Errorf("Test: %s. Expected access token %s, got: %s", name, test.expectedAccessToken, accessToken)
+			}
+		}
+
+		if tokenRequests != test.expectedTokenRequests {
+			t.Errorf("Test: %s. Expected %d token requests, got: %d", name, test.expectedTokenRequests, tokenRequests)
+		}
+
+		tokenServer.Close()
+	}
+}
+
+func Test_GetAccessTokenRotatedRefreshToken(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	receivedRefreshTokens := []string{}
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+
+		receivedRefreshTokens = append(receivedRefreshTokens, r.PostForm.Get("refresh_token"))
+		fmt.Fprintf(w, `{"access_token":"abc","refresh_token":"rotated%d"}`, len(receivedRefreshTokens))
+	}))
+	defer tokenServer.Close()
+
+	config := data.OAuth2{
+		TokenUrl:     tokenServer.URL,
+		ClientId:     "client",
+		GrantType:    data.OAuth2RefreshToken,
+		RefreshToken: "refresh",
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := GetAccessToken(context.Background(), config, data.NewConfig()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	expectedRefreshTokens := []string{"refresh", "rotated1", "rotated2"}
+	if strings.Join(receivedRefreshTokens, " ") != strings.Join(expectedRefreshTokens, " ") {
+		t.Errorf("Expected refresh tokens %v, got: %v", expectedRefreshTokens, receivedRefreshTokens)
+	}
+}
+
+func Test_GetAccessTokenUsesConfig(t *testing.T) {
+	tokenServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"access_token":"abc"}`)
+	}))
+	defer tokenServer.Close()
+
+	insecure := true
+	missingCACert := "missing-ca.pem"
+
+	tests := map[string]struct {
+		config                data.Config
+		expectedErrorContains string
+	}{
+		"Self signed certificate is rejected": {
+			config:                data.NewConfig(),
+			expectedErrorContains: "certificate",
+		},
+		"Insecure skips verification": {
+			config: data.Config{Timeout: data.TimeoutNotSet, Insecure: &insecure},
+		},
+		"Missing CACert is reported": {
+			config:                data.Config{Timeout: data.TimeoutNotSet, CACert: &missingCACert},
+			expectedErrorContains: "CACert=missing-ca.pem",
+		},
+	}
+
+	for name, test := range tests {
+		t.Setenv("XDG_CACHE_HOME", t.TempDir())
+		t.Setenv("HOME", t.TempDir())
+
+		config := data.OAuth2{TokenUrl: tokenServer.URL, ClientId: "client", GrantType: data.OAuth2ClientCredentials}
+		accessToken, err := GetAccessToken(context.Background(), config, test.config)
+
+		if test.expectedErrorContains != "" {
+			if err == nil || !strings.Contains(err.Error(), test.expectedErrorContains) {
+				t.Errorf("Test: %s. Expected error containing %s, got: %v", name, test.expectedErrorContains, err)
+			}
+
+			continue
+		}
+
+		if err != nil || accessToken != "abc" {
+			t.Errorf("Test: %s. Expected access token abc, got: %s %v", name, accessToken, err)
+		}
+	}
+}
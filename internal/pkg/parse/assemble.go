@@ -11,28 +11,60 @@ import (
 	"github.com/jonaslu/ain/internal/pkg/disk"
 )
 
-const editFileSuffix = "!"
+const stdinTemplateFatalName = "<stdin>"
 
-func getAllSectionedTemplates(filenames []string) ([]*sectionedTemplate, error) {
+func getAllSectionedTemplates(filenames []string, substituteEnvVar envVarSubstituter) ([]*sectionedTemplate, []string, error) {
 	allSectionedTemplates := []*sectionedTemplate{}
+	allSectionedTemplatesFatals := []string{}
+	assembledTemplates := map[string]bool{}
 
 	for _, filename := range filenames {
 		editFile := false
 
-		if strings.HasSuffix(filename, editFileSuffix) {
+		if strings.HasSuffix(filename, disk.EditFileSuffix) {
 			editFile = true
-			filename = strings.TrimSuffix(filename, editFileSuffix)
+			filename = strings.TrimSuffix(filename, disk.EditFileSuffix)
 		}
 
-		rawTemplateString, err := disk.ReadRawTemplateString(filename, editFile)
+		templateFilename, requestName := disk.SplitRequestName(filename)
+
+		// Already included by a template before it
+		if templateFilename != disk.StdinTemplateName && assembledTemplates[getAbsoluteFilename(filename)] {
+			continue
+		}
+
+		rawTemplateString, err := disk.ReadRawTemplateString(templateFilename, editFile)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+
+		requestTemplates, fatal := getRequestTemplates(rawTemplateString, getTemplateFatalName(templateFilename), requestName)
+		if fatal != "" {
+			allSectionedTemplatesFatals = append(allSectionedTemplatesFatals, fatal)
+			continue
+		}
+
+		if templateFilename != disk.StdinTemplateName {
+			requestTemplates = dropAssembledPreamble(requestTemplates, templateFilename, requestName, assembledTemplates)
+		}
+
+		templatesWithIncludes, includeFatals := getTemplatesWithIncludes(requestTemplates, []string{filename}, assembledTemplates, substituteEnvVar)
+		if len(includeFatals) > 0 {
+			allSectionedTemplatesFatals = append(allSectionedTemplatesFatals, includeFatals...)
+			continue
+		}
+
+		if templateFilename != disk.StdinTemplateName {
+			assembledTemplates[getAbsoluteFilename(filename)] = true
+			if requestName != "" {
+				assembledTemplates[getPreambleKey(templateFilename)] = true
+			}
 		}
 
-		allSectionedTemplates = append(allSectionedTemplates, newSectionedTemplate(rawTemplateString, filename))
+		allSectionedTemplates = append(allSectionedTemplates, templatesWithIncludes...)
 	}
 
-	return allSectionedTemplates, nil
+	return allSectionedTemplates, allSectionedTemplatesFatals, nil
 }
 
 func getConfig(allSectionedTemplates []*sectionedTemplate) (data.Config, []string) {
@@ -53,34 +85,12 @@ func getConfig(allSectionedTemplates []*sectionedTemplate) (data.Config, []strin
 			break
 		}
 
-		if config.Timeout == data.TimeoutNotSet {
-			config.Timeout = localConfig.Timeout
-		}
-
-		if config.QueryDelim == nil {
-			config.QueryDelim = localConfig.QueryDelim
-		}
-
-		if config.Timeout > data.TimeoutNotSet && config.QueryDelim != nil {
-			break
-		}
+		mergeConfig(&config, localConfig)
 	}
 
 	return config, configFatals
 }
 
-func substituteEnvVars(allSectionedTemplates []*sectionedTemplate) []string {
-	substituteEnvVarsFatals := []string{}
-
-	for _, sectionedTemplate := range allSectionedTemplates {
-		if sectionedTemplate.substituteEnvVars(); sectionedTemplate.hasFatalMessages() {
-			substituteEnvVarsFatals = append(substituteEnvVarsFatals, sectionedTemplate.getFatalMessages())
-		}
-	}
-
-	return substituteEnvVarsFatals
-}
-
 func substituteExecutables(ctx context.Context, config data.Config, allSectionedTemplates []*sectionedTemplate) ([]string, error) {
 	substituteExecutablesFatals := []string{}
 	allExecutableAndArgs := []executableAndArgs{}
@@ -118,6 +128,10 @@ type allSectionRows struct {
 	headers        []string
 	query          []string
 	body           []string
+	cookies        []string
+	auth           *data.Auth
+	oauth2         *data.OAuth2
+	sign           *data.Sign
 	backendOptions [][]string
 }
 
@@ -135,6 +149,7 @@ func getAllSectionRows(allSectionedTemplates []*sectionedTemplate) (allSectionRo
 		allSectionRows.headers = append(allSectionRows.headers, sectionedTemplate.getHeaders()...)
 		allSectionRows.query = append(allSectionRows.query, sectionedTemplate.getQuery()...)
 		allSectionRows.backendOptions = append(allSectionRows.backendOptions, sectionedTemplate.getBackendOptions()...)
+		allSectionRows.cookies = append(allSectionRows.cookies, sectionedTemplate.getCookies()...)
 
 		if localBackend := sectionedTemplate.getBackend(); localBackend != "" {
 			allSectionRows.backend = localBackend
@@ -148,6 +163,18 @@ func getAllSectionRows(allSectionedTemplates []*sectionedTemplate) (allSectionRo
 			allSectionRows.body = localBody
 		}
 
+		if localAuth := sectionedTemplate.getAuth(); localAuth != nil {
+			allSectionRows.auth = localAuth
+		}
+
+		if localOAuth2 := sectionedTemplate.getOAuth2(); localOAuth2 != nil {
+			allSectionRows.oauth2 = mergeOAuth2(allSectionRows.oauth2, localOAuth2)
+		}
+
+		if localSign := sectionedTemplate.getSign(); localSign != nil {
+			allSectionRows.sign = localSign
+		}
+
 		if sectionedTemplate.hasFatalMessages() {
 			allSectionRowsFatals = append(allSectionRowsFatals, sectionedTemplate.getFatalMessages())
 		}
@@ -168,6 +195,17 @@ func getBackendInput(allSectionRows allSectionRows, config data.Config) (*data.B
 		if err != nil {
 			backendInputFatals = append(backendInputFatals, fmt.Sprintf("[Host] has illegal url: %s, error: %v", allSectionRows.host, err))
 		} else {
+			if isUnixSocketHost(hostUrl) {
+				var socketPath string
+				socketPath, hostUrl = splitUnixSocketHost(hostUrl)
+
+				if config.UnixSocket != nil && *config.UnixSocket != socketPath {
+					backendInputFatals = append(backendInputFatals, fmt.Sprintf("[Host] unix socket %s differs from [Config] UnixSocket=%s", socketPath, *config.UnixSocket))
+				}
+
+				config.UnixSocket = &socketPath
+			}
+
 			addQueryString(hostUrl, allSectionRows.query, config)
 			backendInput.Host = hostUrl
 		}
@@ -177,47 +215,85 @@ func getBackendInput(allSectionRows allSectionRows, config data.Config) (*data.B
 		backendInputFatals = append(backendInputFatals, "No mandatory [Backend] section found")
 	}
 
+	if config.ClientKey != nil && config.ClientCert == nil {
+		backendInputFatals = append(backendInputFatals, "[Config] ClientKey needs a ClientCert")
+	}
+
+	if allSectionRows.oauth2 != nil {
+		if allSectionRows.oauth2.GrantType == "" {
+			allSectionRows.oauth2.GrantType = data.OAuth2ClientCredentials
+		}
+
+		backendInputFatals = append(backendInputFatals, getOAuth2Fatals(allSectionRows.oauth2)...)
+
+		if allSectionRows.auth != nil {
+			backendInputFatals = append(backendInputFatals, "Cannot use both [Auth] and [OAuth2]")
+		}
+	}
+
+	if allSectionRows.sign != nil && allSectionRows.sign.Type == data.SignAWSSigV4 && (allSectionRows.auth != nil || allSectionRows.oauth2 != nil) {
+		backendInputFatals = append(backendInputFatals, "Cannot use [Sign] Type=aws-sigv4 together with [Auth] or [OAuth2], both set the Authorization header")
+	}
+
 	backendInput.Method = allSectionRows.method
 	backendInput.Body = allSectionRows.body
 	backendInput.Headers = allSectionRows.headers
+	if len(allSectionRows.cookies) > 0 {
+		backendInput.Headers = append(backendInput.Headers, getCookieHeader(allSectionRows.cookies))
+	}
+	backendInput.Auth = allSectionRows.auth
+	backendInput.OAuth2 = allSectionRows.oauth2
+	backendInput.Sign = allSectionRows.sign
 	backendInput.Backend = allSectionRows.backend
 	backendInput.BackendOptions = allSectionRows.backendOptions
+	backendInput.Config = config
 
 	return &backendInput, backendInputFatals
 }
 
-func Assemble(ctx context.Context, filenames []string) (context.Context, *data.BackendInput, string, error) {
-	allSectionedTemplates, err := getAllSectionedTemplates(filenames)
+// Assemble returns the context bounded by any Timeout in [Config]
+// and its cancel function to release it once the call is done.
+// A backendOverride (ain --as) is used instead of the [Backend],
+// the [BackendOptions] are left as written for the [Backend].
+func Assemble(ctx context.Context, filenames []string, backendOverride string) (context.Context, context.CancelFunc, *data.BackendInput, string, error) {
+	cancel := context.CancelFunc(func() {})
+
+	allSectionedTemplates, allSectionedTemplatesFatals, err := getAllSectionedTemplates(filenames, lookupEnvVar)
 	if err != nil {
-		return ctx, nil, "", err
+		return ctx, cancel, nil, "", err
 	}
 
-	if substituteEnvVarsFatals := substituteEnvVars(allSectionedTemplates); len(substituteEnvVarsFatals) > 0 {
-		return ctx, nil, strings.Join(substituteEnvVarsFatals, "\n\n"), nil
+	if len(allSectionedTemplatesFatals) > 0 {
+		return ctx, cancel, nil, strings.Join(allSectionedTemplatesFatals, "\n\n"), nil
 	}
 
 	config, configFatals := getConfig(allSectionedTemplates)
 	if len(configFatals) > 0 {
-		return ctx, nil, strings.Join(configFatals, "\n\n"), nil
+		return ctx, cancel, nil, strings.Join(configFatals, "\n\n"), nil
 	}
 
 	if config.Timeout != data.TimeoutNotSet {
-		ctx, _ = context.WithTimeout(ctx, time.Duration(config.Timeout)*time.Second)
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(config.Timeout)*time.Second)
 		ctx = context.WithValue(ctx, data.TimeoutContextValueKey{}, config.Timeout)
 	}
 
 	substituteExecutablesFatals, err := substituteExecutables(ctx, config, allSectionedTemplates)
 	if err != nil {
-		return ctx, nil, "", err
+		return ctx, cancel, nil, "", err
 	}
 
 	if len(substituteExecutablesFatals) > 0 {
-		return ctx, nil, strings.Join(substituteExecutablesFatals, "\n\n"), nil
+		return ctx, cancel, nil, strings.Join(substituteExecutablesFatals, "\n\n"), nil
 	}
 
 	allSectionRows, allSectionRowsFatals := getAllSectionRows(allSectionedTemplates)
 	if len(allSectionRowsFatals) > 0 {
-		return ctx, nil, strings.Join(allSectionRowsFatals, "\n\n"), nil
+		return ctx, cancel, nil, strings.Join(allSectionRowsFatals, "\n\n"), nil
+	}
+
+	templateBackend := allSectionRows.backend
+	if backendOverride != "" {
+		allSectionRows.backend = backendOverride
 	}
 
 	backendInput, backendInputFatals := getBackendInput(allSectionRows, config)
@@ -225,8 +301,10 @@ func Assemble(ctx context.Context, filenames []string) (context.Context, *data.B
 		// Since we no longer have a sectionedTemplate errors
 		// are no longer linked to a file and we separate
 		// with one newline
-		return ctx, nil, strings.Join(backendInputFatals, "\n"), nil
+		return ctx, cancel, nil, strings.Join(backendInputFatals, "\n"), nil
 	}
 
-	return ctx, backendInput, "", nil
+	backendInput.TemplateBackend = templateBackend
+
+	return ctx, cancel, backendInput, "", nil
 }
@@ -0,0 +1,61 @@
+package parse
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+	"github.com/jonaslu/ain/internal/pkg/utils"
+)
+
+var authSchemes = []string{data.AuthBasic, data.AuthBearer, data.AuthDigest}
+
+var authLineRe = regexp.MustCompile(`^(\S+)\s*(.*)$`)
+
+func (s *sectionedTemplate) getAuth() *data.Auth {
+	authSourceMarkers := *s.getNamedSection(authSection)
+	if len(authSourceMarkers) == 0 {
+		return nil
+	}
+
+	if len(authSourceMarkers) > 1 {
+		s.setFatalMessage("Found several lines under [Auth]", authSourceMarkers[0].sourceLineIndex)
+		return nil
+	}
+
+	authSourceMarker := authSourceMarkers[0]
+	authLineMatch := authLineRe.FindStringSubmatch(authSourceMarker.lineContents)
+
+	scheme := strings.ToLower(authLineMatch[1])
+	credentials := authLineMatch[2]
+
+	switch scheme {
+	case data.AuthBasic, data.AuthDigest:
+		username, password, found := strings.Cut(credentials, ":")
+		if !found || username == "" {
+			s.setFatalMessage(fmt.Sprintf("Auth %s needs credentials as <username>:<password>", scheme), authSourceMarker.sourceLineIndex)
+			return nil
+		}
+
+		return &data.Auth{Scheme: scheme, Username: username, Password: password}
+
+	case data.AuthBearer:
+		if credentials == "" {
+			s.setFatalMessage("Auth bearer needs a token", authSourceMarker.sourceLineIndex)
+			return nil
+		}
+
+		return &data.Auth{Scheme: scheme, Token: credentials}
+	}
+
+	for _, authScheme := range authSchemes {
+		if utils.LevenshteinDistance(scheme, authScheme) < 3 {
+			s.setFatalMessage(fmt.Sprintf("Unknown auth scheme: %s. Did you mean %s", scheme, authScheme), authSourceMarker.sourceLineIndex)
+			return nil
+		}
+	}
+
+	s.setFatalMessage(fmt.Sprintf("Unknown auth scheme %s, valid schemes are: %s", scheme, strings.Join(authSchemes, ", ")), authSourceMarker.sourceLineIndex)
+	return nil
+}
@@ -23,6 +23,12 @@ func getSectionHeading(templateLineTextTrimmed string) string {
 	return ""
 }
 
+// IsSectionHeading is true if the line would be read as a section
+// heading and needs escaping to be passed on as text
+func IsSectionHeading(templateLineText string) bool {
+	return getSectionHeading(strings.TrimSpace(templateLineText)) != ""
+}
+
 func (s *sectionedTemplate) checkValidHeadings(capturedSections []capturedSection) {
 	// Keeps "header": [1,5,7] <- Name of heading and on what lines in the file
 	headingDefinitionSourceLines := map[string][]int{}
@@ -42,7 +48,8 @@ func (s *sectionedTemplate) checkValidHeadings(capturedSections []capturedSectio
 		}
 
 		for _, headingSourceLineIndex := range headingSourceLineIndexes[1:] {
-			s.setFatalMessage(fmt.Sprintf("Section %s on line %d redeclared", heading, headingSourceLineIndexes[0]+1), headingSourceLineIndex)
+			firstHeadingLine := s.expandedTemplateLines[headingSourceLineIndexes[0]].sourceLineIndex + 1
+			s.setFatalMessage(fmt.Sprintf("Section %s on line %d redeclared", heading, firstHeadingLine), headingSourceLineIndex)
 		}
 	}
 }
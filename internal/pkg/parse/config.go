@@ -1,6 +1,7 @@
 package parse
 
 import (
+	"math"
 	"regexp"
 	"strconv"
 	"strings"
@@ -9,8 +10,8 @@ import (
 	"github.com/pkg/errors"
 )
 
-var timeoutConfigRe = regexp.MustCompile(`(?i)\s*timeout\s*=\s*(-?\d+)?`)
-var queryDelimRe = regexp.MustCompile(`(?i)\s*querydelim\s*=\s*(.*)`)
+var timeoutConfigRe = regexp.MustCompile(`(?i)^\s*timeout\s*=\s*(-?\d+)?`)
+var queryDelimRe = regexp.MustCompile(`(?i)^\s*querydelim\s*=\s*(.*)`)
 
 func parseQueryDelim(configStr string) (bool, string, error) {
 	queryDelimMatch := queryDelimRe.FindStringSubmatch(configStr)
@@ -26,6 +27,143 @@ func parseQueryDelim(configStr string) (bool, string, error) {
 	return true, queryDelimMatch[1], nil
 }
 
+// Valid HTTPVersion config values
+var httpVersions = []string{"1.0", "1.1", "2", "3"}
+
+// Config keys with a text value, such as file names.
+// If validValues is set the value must be one of them.
+var stringConfigs = []struct {
+	configRe    *regexp.Regexp
+	name        string
+	value       func(*data.Config) **string
+	validValues []string
+}{
+	{regexp.MustCompile(`(?i)^\s*cookiejar\s*=\s*(.*)`), "Cookie jar", func(c *data.Config) **string { return &c.CookieJar }, nil},
+	{regexp.MustCompile(`(?i)^\s*proxy\s*=\s*(.*)`), "Proxy", func(c *data.Config) **string { return &c.Proxy }, nil},
+	{regexp.MustCompile(`(?i)^\s*cacert\s*=\s*(.*)`), "CA cert", func(c *data.Config) **string { return &c.CACert }, nil},
+	{regexp.MustCompile(`(?i)^\s*clientcert\s*=\s*(.*)`), "Client cert", func(c *data.Config) **string { return &c.ClientCert }, nil},
+	{regexp.MustCompile(`(?i)^\s*clientkey\s*=\s*(.*)`), "Client key", func(c *data.Config) **string { return &c.ClientKey }, nil},
+	{regexp.MustCompile(`(?i)^\s*unixsocket\s*=\s*(.*)`), "Unix socket", func(c *data.Config) **string { return &c.UnixSocket }, nil},
+	{regexp.MustCompile(`(?i)^\s*httpversion\s*=\s*(.*)`), "HTTP version", func(c *data.Config) **string { return &c.HTTPVersion }, httpVersions},
+}
+
+// Config keys that are on or off. The key alone turns it on.
+var boolConfigs = []struct {
+	configRe *regexp.Regexp
+	name     string
+	value    func(*data.Config) **bool
+}{
+	{regexp.MustCompile(`(?i)^\s*insecure\s*(?:=\s*(.*))?$`), "Insecure", func(c *data.Config) **bool { return &c.Insecure }},
+	{regexp.MustCompile(`(?i)^\s*followredirects\s*(?:=\s*(.*))?$`), "Follow redirects", func(c *data.Config) **bool { return &c.FollowRedirects }},
+	{regexp.MustCompile(`(?i)^\s*verbose\s*(?:=\s*(.*))?$`), "Verbose", func(c *data.Config) **bool { return &c.Verbose }},
+	{regexp.MustCompile(`(?i)^\s*compressed\s*(?:=\s*(.*))?$`), "Compressed", func(c *data.Config) **bool { return &c.Compressed }},
+}
+
+// Config keys with a whole number value
+var intConfigs = []struct {
+	configRe *regexp.Regexp
+	name     string
+	value    func(*data.Config) **int32
+	minValue int64
+	maxValue int64
+}{
+	{regexp.MustCompile(`(?i)^\s*maxredirects\s*=\s*(.*)`), "Max redirects", func(c *data.Config) **int32 { return &c.MaxRedirects }, 0, math.MaxInt32},
+	{regexp.MustCompile(`(?i)^\s*connecttimeout\s*=\s*(.*)`), "Connect timeout", func(c *data.Config) **int32 { return &c.ConnectTimeout }, 1, math.MaxInt32},
+	{regexp.MustCompile(`(?i)^\s*retries\s*=\s*(.*)`), "Retries", func(c *data.Config) **int32 { return &c.Retries }, 0, 100},
+	{regexp.MustCompile(`(?i)^\s*retrybackoff\s*=\s*(.*)`), "Retry backoff", func(c *data.Config) **int32 { return &c.RetryBackoff }, 0, math.MaxInt32},
+}
+
+// Config keys with a list of whole numbers, separated by comma or space
+var intListConfigs = []struct {
+	configRe *regexp.Regexp
+	name     string
+	value    func(*data.Config) *[]int
+}{
+	{regexp.MustCompile(`(?i)^\s*retrystatus\s*=\s*(.*)`), "Retry status", func(c *data.Config) *[]int { return &c.RetryStatus }},
+	{regexp.MustCompile(`(?i)^\s*retryexitcodes\s*=\s*(.*)`), "Retry exit codes", func(c *data.Config) *[]int { return &c.RetryExitCodes }},
+}
+
+func parseStringConfig(configRe *regexp.Regexp, name, configStr string, validValues []string) (bool, string, error) {
+	stringConfigMatch := configRe.FindStringSubmatch(configStr)
+	if len(stringConfigMatch) != 2 {
+		return false, "", nil
+	}
+
+	value := strings.TrimSpace(stringConfigMatch[1])
+	if value == "" {
+		return true, "", errors.Errorf("%s needs a value", name)
+	}
+
+	if len(validValues) == 0 {
+		return true, value, nil
+	}
+
+	for _, validValue := range validValues {
+		if value == validValue {
+			return true, value, nil
+		}
+	}
+
+	return true, "", errors.Errorf("%s must be one of: %s", name, strings.Join(validValues, ", "))
+}
+
+func parseIntConfig(configRe *regexp.Regexp, name, configStr string, minValue, maxValue int64) (bool, int32, error) {
+	intConfigMatch := configRe.FindStringSubmatch(configStr)
+	if len(intConfigMatch) != 2 {
+		return false, 0, nil
+	}
+
+	value, err := strconv.ParseInt(strings.TrimSpace(intConfigMatch[1]), 10, 32)
+	if err != nil || value < minValue || value > maxValue {
+		if maxValue < math.MaxInt32 {
+			return true, 0, errors.Errorf("%s must be a whole number between %d and %d", name, minValue, maxValue)
+		}
+
+		return true, 0, errors.Errorf("%s must be a whole number >= %d", name, minValue)
+	}
+
+	return true, int32(value), nil
+}
+
+func parseIntListConfig(configRe *regexp.Regexp, name, configStr string) (bool, []int, error) {
+	intListConfigMatch := configRe.FindStringSubmatch(configStr)
+	if len(intListConfigMatch) != 2 {
+		return false, nil, nil
+	}
+
+	values := []int{}
+	for _, valueStr := range strings.FieldsFunc(intListConfigMatch[1], func(r rune) bool { return r == ',' || r == ' ' }) {
+		value, err := strconv.Atoi(valueStr)
+		if err != nil || value < 0 {
+			return true, nil, errors.Errorf("%s must be whole numbers separated by comma, got: %s", name, valueStr)
+		}
+
+		values = append(values, value)
+	}
+
+	if len(values) == 0 {
+		return true, nil, errors.Errorf("%s needs a value", name)
+	}
+
+	return true, values, nil
+}
+
+func parseBoolConfig(configRe *regexp.Regexp, name, configStr string) (bool, bool, error) {
+	boolConfigMatch := configRe.FindStringSubmatch(configStr)
+	if len(boolConfigMatch) != 2 {
+		return false, false, nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(boolConfigMatch[1])) {
+	case "", "true", "yes":
+		return true, true, nil
+	case "false", "no":
+		return true, false, nil
+	}
+
+	return true, false, errors.Errorf("%s must be true or false", name)
+}
+
 func parseTimeoutConfig(configStr string) (bool, int32, error) {
 	timeoutMatch := timeoutConfigRe.FindStringSubmatch(configStr)
 	if len(timeoutMatch) != 2 {
@@ -85,7 +223,118 @@ func (s *sectionedTemplate) getConfig() data.Config {
 			config.QueryDelim = &queryDelimValue
 			continue
 		}
+
+		for _, stringConfig := range stringConfigs {
+			isStringConfig, stringValue, err := parseStringConfig(stringConfig.configRe, stringConfig.name, configLine.lineContents, stringConfig.validValues)
+			if !isStringConfig {
+				continue
+			}
+
+			if *stringConfig.value(&config) != nil {
+				s.setFatalMessage(stringConfig.name+" set twice", configLine.sourceLineIndex)
+				return config
+			}
+
+			if err != nil {
+				s.setFatalMessage(err.Error(), configLine.sourceLineIndex)
+				return config
+			}
+
+			*stringConfig.value(&config) = &stringValue
+		}
+
+		for _, boolConfig := range boolConfigs {
+			isBoolConfig, boolValue, err := parseBoolConfig(boolConfig.configRe, boolConfig.name, configLine.lineContents)
+			if !isBoolConfig {
+				continue
+			}
+
+			if *boolConfig.value(&config) != nil {
+				s.setFatalMessage(boolConfig.name+" set twice", configLine.sourceLineIndex)
+				return config
+			}
+
+			if err != nil {
+				s.setFatalMessage(err.Error(), configLine.sourceLineIndex)
+				return config
+			}
+
+			*boolConfig.value(&config) = &boolValue
+		}
+
+		for _, intConfig := range intConfigs {
+			isIntConfig, intValue, err := parseIntConfig(intConfig.configRe, intConfig.name, configLine.lineContents, intConfig.minValue, intConfig.maxValue)
+			if !isIntConfig {
+				continue
+			}
+
+			if *intConfig.value(&config) != nil {
+				s.setFatalMessage(intConfig.name+" set twice", configLine.sourceLineIndex)
+				return config
+			}
+
+			if err != nil {
+				s.setFatalMessage(err.Error(), configLine.sourceLineIndex)
+				return config
+			}
+
+			*intConfig.value(&config) = &intValue
+		}
+
+		for _, intListConfig := range intListConfigs {
+			isIntListConfig, intListValue, err := parseIntListConfig(intListConfig.configRe, intListConfig.name, configLine.lineContents)
+			if !isIntListConfig {
+				continue
+			}
+
+			if *intListConfig.value(&config) != nil {
+				s.setFatalMessage(intListConfig.name+" set twice", configLine.sourceLineIndex)
+				return config
+			}
+
+			if err != nil {
+				s.setFatalMessage(err.Error(), configLine.sourceLineIndex)
+				return config
+			}
+
+			*intListConfig.value(&config) = intListValue
+		}
 	}
 
 	return config
 }
+
+// mergeConfig sets any config not set in config from localConfig
+func mergeConfig(config *data.Config, localConfig data.Config) {
+	if config.Timeout == data.TimeoutNotSet {
+		config.Timeout = localConfig.Timeout
+	}
+
+	if config.QueryDelim == nil {
+		config.QueryDelim = localConfig.QueryDelim
+	}
+
+	for _, stringConfig := range stringConfigs {
+		if *stringConfig.value(config) == nil {
+			*stringConfig.value(config) = *stringConfig.value(&localConfig)
+		}
+	}
+
+	for _, boolConfig := range boolConfigs {
+		if *boolConfig.value(config) == nil {
+			*boolConfig.value(config) = *boolConfig.value(&localConfig)
+		}
+	}
+
+	for _, intConfig := range intConfigs {
+		if *intConfig.value(config) == nil {
+			*intConfig.value(config) = *intConfig.value(&localConfig)
+		}
+	}
+
+	for _, intListConfig := range intListConfigs {
+		if *intListConfig.value(config) == nil {
+			*intListConfig.value(config) = *intListConfig.value(&localConfig)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package parse
+
+import (
+	"strings"
+)
+
+func (s *sectionedTemplate) getCookies() []string {
+	var cookies []string
+
+	for _, cookieSourceMarker := range *s.getNamedSection(cookiesSection) {
+		name, value, found := strings.Cut(cookieSourceMarker.lineContents, "=")
+		if !found || strings.TrimSpace(name) == "" {
+			s.setFatalMessage("[Cookies] lines must be <name>=<value>", cookieSourceMarker.sourceLineIndex)
+			continue
+		}
+
+		cookies = append(cookies, strings.TrimSpace(name)+"="+strings.TrimSpace(value))
+	}
+
+	return cookies
+}
+
+func getCookieHeader(cookies []string) string {
+	return "Cookie: " + strings.Join(cookies, "; ")
+}
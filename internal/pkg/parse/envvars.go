@@ -42,25 +42,32 @@ func formatMissingEnvVarErrorMessage(missingEnvVar string) string {
 	return fmt.Sprintf("Cannot find value for variable %s", missingEnvVar)
 }
 
-func (s *sectionedTemplate) substituteEnvVars() {
+// envVarSubstituter returns the value of a variable or a fatal message
+type envVarSubstituter func(envVarKey string) (string, string)
+
+func lookupEnvVar(envVarKey string) (string, string) {
+	// I'll try anything that is not empty, if the user can't set (such as a variable with spaces in bash) it we can't find it anyway.
+	// https://stackoverflow.com/questions/2821043/allowed-characters-in-linux-environment-variable-names
+	value, exists := os.LookupEnv(envVarKey)
+
+	if !exists {
+		return "", formatMissingEnvVarErrorMessage(envVarKey)
+	}
+
+	if value == "" {
+		return "", fmt.Sprintf("Value for variable %s is empty", envVarKey)
+	}
+
+	return value, ""
+}
+
+func (s *sectionedTemplate) substituteEnvVars(substituteEnvVar envVarSubstituter) {
 	s.expandTemplateLines(tokenizeEnvVars, func(c token) (string, string) {
 		envVarKey := c.content
 		if envVarKey == "" {
 			return "", "Empty variable"
 		}
 
-		// I'll try anything that is not empty, if the user can't set (such as a variable with spaces in bash) it we can't find it anyway.
-		// https://stackoverflow.com/questions/2821043/allowed-characters-in-linux-environment-variable-names
-		value, exists := os.LookupEnv(envVarKey)
-
-		if !exists {
-			return "", formatMissingEnvVarErrorMessage(envVarKey)
-		}
-
-		if value == "" {
-			return "", fmt.Sprintf("Value for variable %s is empty", envVarKey)
-		}
-
-		return value, ""
+		return substituteEnvVar(envVarKey)
 	})
 }
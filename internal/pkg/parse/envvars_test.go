@@ -44,7 +44,7 @@ func Test_sectionedTemplate_expandEnvVars_GoodCases(t *testing.T) {
 		test.beforeTest()
 		s := newSectionedTemplate(test.inputTemplate, "")
 
-		if s.substituteEnvVars(); s.hasFatalMessages() {
+		if s.substituteEnvVars(lookupEnvVar); s.hasFatalMessages() {
 			t.Errorf("Got unexpected fatals, %s ", s.getFatalMessages())
 		} else {
 			if !reflect.DeepEqual(test.expectedResult, s.expandedTemplateLines) {
@@ -84,7 +84,7 @@ func Test_sectionedTemplate_expandEnvVars_BadCases(t *testing.T) {
 	for name, test := range tests {
 		test.beforeTest()
 		s := newSectionedTemplate(test.input, "")
-		s.substituteEnvVars()
+		s.substituteEnvVars(lookupEnvVar)
 
 		if len(s.fatals) != 1 {
 			t.Errorf("Test: %s. Wrong number of fatals", name)
@@ -0,0 +1,84 @@
+package parse
+
+import (
+	"strings"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+)
+
+// ExportRequest is the assembled sections of a request with
+// the variables and executables kept to be exported as is
+type ExportRequest struct {
+	Host           string
+	Query          []string
+	Method         string
+	Headers        []string
+	Body           []string
+	Auth           *data.Auth
+	OAuth2         *data.OAuth2
+	Sign           *data.Sign
+	BackendOptions [][]string
+}
+
+func (s *sectionedTemplate) formatExecutables(formatExecutable func(string) string) {
+	s.expandTemplateLines(tokenizeExecutables, func(c token) (string, string) {
+		if c.content == "" {
+			return "", "Empty executable"
+		}
+
+		return formatExecutable(c.content), ""
+	})
+}
+
+// AssembleForExport assembles the templates the same as Assemble, but
+// instead of substituting variables and running executables they're
+// formatted for the exported format, e g ${VAR} as {{VAR}}
+func AssembleForExport(filenames []string, formatVariable, formatExecutable func(string) string) (*ExportRequest, string, error) {
+	allSectionedTemplates, allSectionedTemplatesFatals, err := getAllSectionedTemplates(filenames, func(envVarKey string) (string, string) {
+		return formatVariable(envVarKey), ""
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(allSectionedTemplatesFatals) > 0 {
+		return nil, strings.Join(allSectionedTemplatesFatals, "\n\n"), nil
+	}
+
+	formatExecutablesFatals := []string{}
+	for _, sectionedTemplate := range allSectionedTemplates {
+		if sectionedTemplate.formatExecutables(formatExecutable); sectionedTemplate.hasFatalMessages() {
+			formatExecutablesFatals = append(formatExecutablesFatals, sectionedTemplate.getFatalMessages())
+		}
+	}
+
+	if len(formatExecutablesFatals) > 0 {
+		return nil, strings.Join(formatExecutablesFatals, "\n\n"), nil
+	}
+
+	allSectionRows, allSectionRowsFatals := getAllSectionRows(allSectionedTemplates)
+	if len(allSectionRowsFatals) > 0 {
+		return nil, strings.Join(allSectionRowsFatals, "\n\n"), nil
+	}
+
+	if allSectionRows.host == "" {
+		return nil, "No mandatory [Host] section found", nil
+	}
+
+	headers := allSectionRows.headers
+	if len(allSectionRows.cookies) > 0 {
+		headers = append(headers, getCookieHeader(allSectionRows.cookies))
+	}
+
+	return &ExportRequest{
+		Host:           allSectionRows.host,
+		Query:          allSectionRows.query,
+		Method:         allSectionRows.method,
+		Headers:        headers,
+		Body:           allSectionRows.body,
+		Auth:           allSectionRows.auth,
+		OAuth2:         allSectionRows.oauth2,
+		Sign:           allSectionRows.sign,
+		BackendOptions: allSectionRows.backendOptions,
+	}, "", nil
+}
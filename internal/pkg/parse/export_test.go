@@ -0,0 +1,41 @@
+package parse
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAssembleForExport(t *testing.T) {
+	templateDir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(templateDir, "base.ain"), []byte("[Host]\n${HOST}\n\n[Headers]\nAccept: */*\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	templateFileName := filepath.Join(templateDir, "get-user.ain")
+	template := "[Include]\nbase.ain\n\n[Host]\n/users/${USER_ID}\n\n[Headers]\nX-Request-Id: $(uuidgen)\n\n[Cookies]\nsession=`${literal}\n"
+	if err := os.WriteFile(templateFileName, []byte(template), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	exportRequest, fatal, err := AssembleForExport([]string{templateFileName}, func(envVarKey string) string {
+		return "{{" + envVarKey + "}}"
+	}, func(executable string) string {
+		return "<" + executable + ">"
+	})
+
+	if err != nil || fatal != "" {
+		t.Fatalf("Unexpected error: %v %s", err, fatal)
+	}
+
+	if exportRequest.Host != "{{HOST}}/users/{{USER_ID}}" {
+		t.Errorf("Expected host: {{HOST}}/users/{{USER_ID}}, Got: %s", exportRequest.Host)
+	}
+
+	expectedHeaders := []string{"Accept: */*", "X-Request-Id: <uuidgen>", "Cookie: session=${literal}"}
+	if !reflect.DeepEqual(exportRequest.Headers, expectedHeaders) {
+		t.Errorf("Expected headers: %v, Got: %v", expectedHeaders, exportRequest.Headers)
+	}
+}
@@ -62,6 +62,12 @@ func (s *sectionedTemplate) setFatalMessage(msg string, expandedSourceLineIndex
 	s.fatals = append(s.fatals, message)
 }
 
+// setFileFatalMessage is for fatals concerning the
+// whole template and not a particular line
+func (s *sectionedTemplate) setFileFatalMessage(msg string) {
+	s.fatals = append(s.fatals, msg)
+}
+
 func (s *sectionedTemplate) getFatalMessages() string {
 	fatalMessage := "Fatal error"
 	if len(s.fatals) > 1 {
@@ -70,6 +76,10 @@ func (s *sectionedTemplate) getFatalMessages() string {
 
 	fatalMessage = fatalMessage + " in file: " + s.filename + "\n"
 
+	if s.includeChain != "" {
+		fatalMessage = fatalMessage + "Included from: " + s.includeChain + "\n"
+	}
+
 	return fatalMessage + strings.Join(s.fatals, "\n\n")
 }
 
@@ -0,0 +1,183 @@
+package parse
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jonaslu/ain/internal/pkg/disk"
+)
+
+const includeChainSeparator = " -> "
+
+func getTemplateFatalName(filename string) string {
+	if templateFilename, requestName := disk.SplitRequestName(filename); templateFilename == disk.StdinTemplateName {
+		if requestName != "" {
+			return stdinTemplateFatalName + disk.RequestNameSeparator + requestName
+		}
+
+		return stdinTemplateFatalName
+	}
+
+	return filename
+}
+
+func formatIncludeChain(includeChain []string) string {
+	fatalNames := []string{}
+	for _, filename := range includeChain {
+		fatalNames = append(fatalNames, getTemplateFatalName(filename))
+	}
+
+	return strings.Join(fatalNames, includeChainSeparator)
+}
+
+// Included files are relative to the file including them.
+// Templates from stdin are relative to the current working dir.
+func getIncludeFilename(includingFilename, includeLine string) string {
+	if filepath.IsAbs(includeLine) {
+		return filepath.Clean(includeLine)
+	}
+
+	return filepath.Join(filepath.Dir(includingFilename), includeLine)
+}
+
+func getAbsoluteFilename(filename string) string {
+	if absoluteFilename, err := filepath.Abs(filename); err == nil {
+		return absoluteFilename
+	}
+
+	return filename
+}
+
+// getPreambleKey keeps track of the preamble of a file split into
+// requests, it's shared by the requests and only assembled once
+func getPreambleKey(templateFilename string) string {
+	return getAbsoluteFilename(templateFilename) + disk.RequestNameSeparator
+}
+
+// dropAssembledPreamble leaves out the preamble when a request is
+// selected (the templates are then preamble and request) and an
+// earlier request from the same file already assembled it
+func dropAssembledPreamble(requestTemplates []*sectionedTemplate, templateFilename, requestName string, assembledTemplates map[string]bool) []*sectionedTemplate {
+	if requestName != "" && assembledTemplates[getPreambleKey(templateFilename)] {
+		return requestTemplates[1:]
+	}
+
+	return requestTemplates
+}
+
+func findInIncludeChain(includeChain []string, absoluteFilename string) bool {
+	for _, filename := range includeChain {
+		if getAbsoluteFilename(filename) == absoluteFilename {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getTemplatesWithIncludes substitutes env-vars in the templates read from one
+// file and puts any templates they include before them. Env-vars are substituted
+// before any [Include]:s are read so the included file names can contain variables.
+func getTemplatesWithIncludes(
+	fileTemplates []*sectionedTemplate,
+	includeChain []string,
+	assembledTemplates map[string]bool,
+	substituteEnvVar envVarSubstituter,
+) ([]*sectionedTemplate, []string) {
+	templatesWithIncludes := []*sectionedTemplate{}
+	includeFatals := []string{}
+
+	for _, fileTemplate := range fileTemplates {
+		if fileTemplate.substituteEnvVars(substituteEnvVar); fileTemplate.hasFatalMessages() {
+			includeFatals = append(includeFatals, fileTemplate.getFatalMessages())
+			continue
+		}
+
+		includedTemplates, nestedIncludeFatals := getIncludedTemplates(fileTemplate, includeChain, assembledTemplates, substituteEnvVar)
+		if len(nestedIncludeFatals) > 0 {
+			includeFatals = append(includeFatals, nestedIncludeFatals...)
+			continue
+		}
+
+		templatesWithIncludes = append(templatesWithIncludes, includedTemplates...)
+		templatesWithIncludes = append(templatesWithIncludes, fileTemplate)
+	}
+
+	return templatesWithIncludes, includeFatals
+}
+
+// getIncludedTemplates reads any templates in the [Include] section
+// of the last template in the includeChain, depth first. Each template
+// is only included once, assembledTemplates keeps track of what has
+// been included so far.
+func getIncludedTemplates(
+	includingTemplate *sectionedTemplate,
+	includeChain []string,
+	assembledTemplates map[string]bool,
+	substituteEnvVar envVarSubstituter,
+) ([]*sectionedTemplate, []string) {
+	if includingTemplate.setCapturedSections(includeSection); includingTemplate.hasFatalMessages() {
+		return nil, []string{includingTemplate.getFatalMessages()}
+	}
+
+	includedTemplates := []*sectionedTemplate{}
+	includeFatals := []string{}
+
+	includingFilename := includeChain[len(includeChain)-1]
+
+	for _, includeSourceMarker := range *includingTemplate.getNamedSection(includeSection) {
+		includeFilename := getIncludeFilename(includingFilename, includeSourceMarker.lineContents)
+		absoluteIncludeFilename := getAbsoluteFilename(includeFilename)
+
+		if findInIncludeChain(includeChain, absoluteIncludeFilename) {
+			cycleIncludeChain := append(append([]string{}, includeChain...), includeFilename)
+			includingTemplate.setFatalMessage(fmt.Sprintf("Include cycle %s", formatIncludeChain(cycleIncludeChain)), includeSourceMarker.sourceLineIndex)
+
+			continue
+		}
+
+		if assembledTemplates[absoluteIncludeFilename] {
+			continue
+		}
+
+		includeTemplateFilename, requestName := disk.SplitRequestName(includeFilename)
+
+		rawTemplateString, err := disk.ReadRawTemplateString(includeTemplateFilename, false)
+		if err != nil {
+			includingTemplate.setFatalMessage(fmt.Sprintf("Cannot include template: %v", err), includeSourceMarker.sourceLineIndex)
+			continue
+		}
+
+		requestTemplates, fatal := getRequestTemplates(rawTemplateString, includeTemplateFilename, requestName)
+		if fatal != "" {
+			includeFatals = append(includeFatals, fatal)
+			continue
+		}
+
+		requestTemplates = dropAssembledPreamble(requestTemplates, includeTemplateFilename, requestName, assembledTemplates)
+		for _, requestTemplate := range requestTemplates {
+			requestTemplate.includeChain = formatIncludeChain(includeChain)
+		}
+
+		nestedIncludeChain := append(append([]string{}, includeChain...), includeFilename)
+		nestedTemplates, nestedIncludeFatals := getTemplatesWithIncludes(requestTemplates, nestedIncludeChain, assembledTemplates, substituteEnvVar)
+		if len(nestedIncludeFatals) > 0 {
+			includeFatals = append(includeFatals, nestedIncludeFatals...)
+			continue
+		}
+
+		assembledTemplates[absoluteIncludeFilename] = true
+		if requestName != "" {
+			assembledTemplates[getPreambleKey(includeTemplateFilename)] = true
+		}
+
+		includedTemplates = append(includedTemplates, nestedTemplates...)
+	}
+
+	if includingTemplate.hasFatalMessages() {
+		includeFatals = append(includeFatals, includingTemplate.getFatalMessages())
+	}
+
+	return includedTemplates, includeFatals
+}
@@ -0,0 +1,135 @@
+package parse
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+	"github.com/jonaslu/ain/internal/pkg/utils"
+)
+
+// A <key>=<value> line as in the [OAuth2] and [Sign] sections
+var keyValueLineRe = regexp.MustCompile(`^\s*([^=\s]+)\s*=\s*(.*)$`)
+
+// Lowercase keys in the [OAuth2] section and what they set
+var oauth2Keys = map[string]func(*data.OAuth2, string){
+	"tokenurl":     func(o *data.OAuth2, v string) { o.TokenUrl = v },
+	"clientid":     func(o *data.OAuth2, v string) { o.ClientId = v },
+	"clientsecret": func(o *data.OAuth2, v string) { o.ClientSecret = v },
+	"scope":        func(o *data.OAuth2, v string) { o.Scope = v },
+	"granttype":    func(o *data.OAuth2, v string) { o.GrantType = v },
+	"refreshtoken": func(o *data.OAuth2, v string) { o.RefreshToken = v },
+}
+
+var oauth2KeyNames = []string{"TokenUrl", "ClientId", "ClientSecret", "Scope", "GrantType", "RefreshToken"}
+
+func getOAuth2KeySuggestion(key string) string {
+	for _, oauth2KeyName := range oauth2KeyNames {
+		if utils.LevenshteinDistance(key, strings.ToLower(oauth2KeyName)) < 3 {
+			return oauth2KeyName
+		}
+	}
+
+	return ""
+}
+
+// getOAuth2 returns the keys set in this template. Keys left
+// empty are filled in from the templates before it.
+func (s *sectionedTemplate) getOAuth2() *data.OAuth2 {
+	oauth2SourceMarkers := *s.getNamedSection(oauth2Section)
+	if len(oauth2SourceMarkers) == 0 {
+		return nil
+	}
+
+	oauth2 := data.OAuth2{}
+	seenKeys := map[string]bool{}
+
+	for _, oauth2SourceMarker := range oauth2SourceMarkers {
+		oauth2LineMatch := keyValueLineRe.FindStringSubmatch(oauth2SourceMarker.lineContents)
+		if oauth2LineMatch == nil {
+			s.setFatalMessage("[OAuth2] lines must be <key>=<value>", oauth2SourceMarker.sourceLineIndex)
+			continue
+		}
+
+		key := strings.ToLower(oauth2LineMatch[1])
+		setValue, exists := oauth2Keys[key]
+		if !exists {
+			if suggestion := getOAuth2KeySuggestion(key); suggestion != "" {
+				s.setFatalMessage(fmt.Sprintf("Unknown [OAuth2] key: %s. Did you mean %s", oauth2LineMatch[1], suggestion), oauth2SourceMarker.sourceLineIndex)
+			} else {
+				s.setFatalMessage(fmt.Sprintf("Unknown [OAuth2] key %s, valid keys are: %s", oauth2LineMatch[1], strings.Join(oauth2KeyNames, ", ")), oauth2SourceMarker.sourceLineIndex)
+			}
+
+			continue
+		}
+
+		if seenKeys[key] {
+			s.setFatalMessage(fmt.Sprintf("[OAuth2] key %s set twice", oauth2LineMatch[1]), oauth2SourceMarker.sourceLineIndex)
+			continue
+		}
+
+		seenKeys[key] = true
+		setValue(&oauth2, oauth2LineMatch[2])
+	}
+
+	return &oauth2
+}
+
+func mergeOAuth2(base, local *data.OAuth2) *data.OAuth2 {
+	if base == nil {
+		return local
+	}
+
+	merged := *base
+
+	if local.TokenUrl != "" {
+		merged.TokenUrl = local.TokenUrl
+	}
+
+	if local.ClientId != "" {
+		merged.ClientId = local.ClientId
+	}
+
+	if local.ClientSecret != "" {
+		merged.ClientSecret = local.ClientSecret
+	}
+
+	if local.Scope != "" {
+		merged.Scope = local.Scope
+	}
+
+	if local.GrantType != "" {
+		merged.GrantType = local.GrantType
+	}
+
+	if local.RefreshToken != "" {
+		merged.RefreshToken = local.RefreshToken
+	}
+
+	return &merged
+}
+
+func getOAuth2Fatals(oauth2 *data.OAuth2) []string {
+	oauth2Fatals := []string{}
+
+	if oauth2.TokenUrl == "" {
+		oauth2Fatals = append(oauth2Fatals, "[OAuth2] is missing mandatory TokenUrl")
+	}
+
+	if oauth2.ClientId == "" {
+		oauth2Fatals = append(oauth2Fatals, "[OAuth2] is missing mandatory ClientId")
+	}
+
+	switch oauth2.GrantType {
+	case data.OAuth2ClientCredentials:
+	case data.OAuth2RefreshToken:
+		if oauth2.RefreshToken == "" {
+			oauth2Fatals = append(oauth2Fatals, "[OAuth2] GrantType refresh_token needs a RefreshToken")
+		}
+	default:
+		oauth2Fatals = append(oauth2Fatals, fmt.Sprintf("Unknown [OAuth2] GrantType %s, valid grant types are: %s, %s", oauth2.GrantType, data.OAuth2ClientCredentials, data.OAuth2RefreshToken))
+	}
+
+	return oauth2Fatals
+}
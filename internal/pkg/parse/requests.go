@@ -0,0 +1,146 @@
+package parse
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jonaslu/ain/internal/pkg/disk"
+	"github.com/jonaslu/ain/internal/pkg/utils"
+)
+
+// A line with ### <name> starts a named request in a template file.
+// It's only read as such when a request is selected or listed,
+// otherwise it's a comment as in any template.
+var requestSeparatorRe = regexp.MustCompile(`^\s*###\s*([^\s#]+)\s*$`)
+
+type requestBlock struct {
+	name          string
+	separatorLine int
+	// Lines between the separator and the next request
+	fromLine, toLine int
+}
+
+// splitRequestBlocks returns where the shared preamble
+// ends and any named requests in the template lines
+func splitRequestBlocks(rawTemplateLines []string) (int, []requestBlock) {
+	preambleToLine := len(rawTemplateLines)
+	requestBlocks := []requestBlock{}
+
+	for lineIndex, rawTemplateLine := range rawTemplateLines {
+		requestSeparatorMatch := requestSeparatorRe.FindStringSubmatch(rawTemplateLine)
+		if requestSeparatorMatch == nil {
+			continue
+		}
+
+		if len(requestBlocks) == 0 {
+			preambleToLine = lineIndex
+		} else {
+			requestBlocks[len(requestBlocks)-1].toLine = lineIndex
+		}
+
+		requestBlocks = append(requestBlocks, requestBlock{
+			name:          requestSeparatorMatch[1],
+			separatorLine: lineIndex,
+			fromLine:      lineIndex + 1,
+			toLine:        len(rawTemplateLines),
+		})
+	}
+
+	return preambleToLine, requestBlocks
+}
+
+func getRequestNames(requestBlocks []requestBlock) []string {
+	requestNames := []string{}
+	for _, requestBlock := range requestBlocks {
+		requestNames = append(requestNames, requestBlock.name)
+	}
+
+	return requestNames
+}
+
+func findRequestBlock(requestBlocks []requestBlock, requestName string) (requestBlock, string) {
+	for _, requestBlock := range requestBlocks {
+		if requestBlock.name == requestName {
+			return requestBlock, ""
+		}
+	}
+
+	for _, requestBlock := range requestBlocks {
+		if utils.LevenshteinDistance(requestName, requestBlock.name) < 3 {
+			return requestBlock, fmt.Sprintf("Unknown request: %s. Did you mean %s", requestName, requestBlock.name)
+		}
+	}
+
+	return requestBlock{}, fmt.Sprintf("Unknown request %s, the file has requests: %s", requestName, strings.Join(getRequestNames(requestBlocks), ", "))
+}
+
+// getRequestTemplates returns the templates to assemble from a template file.
+// When a request is selected it's the shared preamble and the selected
+// request, each as a separate template. Otherwise the whole file.
+func getRequestTemplates(rawTemplateString, filename, requestName string) ([]*sectionedTemplate, string) {
+	fileTemplate := newSectionedTemplate(rawTemplateString, filename)
+	if requestName == "" {
+		return []*sectionedTemplate{fileTemplate}, ""
+	}
+
+	rawTemplateLines := fileTemplate.rawTemplateLines
+	preambleToLine, requestBlocks := splitRequestBlocks(rawTemplateLines)
+
+	if len(requestBlocks) == 0 {
+		fileTemplate.setFileFatalMessage(fmt.Sprintf("Cannot select request %s, the file contains no ### <name> requests", requestName))
+		return nil, fileTemplate.getFatalMessages()
+	}
+
+	requestNameLines := map[string]int{}
+	for _, requestBlock := range requestBlocks {
+		if firstSeparatorLine, exists := requestNameLines[requestBlock.name]; exists {
+			fileTemplate.setFatalMessage(fmt.Sprintf("Request %s on line %d redeclared", requestBlock.name, firstSeparatorLine+1), requestBlock.separatorLine)
+			continue
+		}
+
+		requestNameLines[requestBlock.name] = requestBlock.separatorLine
+	}
+
+	if fileTemplate.hasFatalMessages() {
+		return nil, fileTemplate.getFatalMessages()
+	}
+
+	selectedRequestBlock, fatal := findRequestBlock(requestBlocks, requestName)
+	if fatal != "" {
+		fileTemplate.setFileFatalMessage(fatal)
+		return nil, fileTemplate.getFatalMessages()
+	}
+
+	return []*sectionedTemplate{
+		newSectionedTemplateFromLines(rawTemplateLines, 0, preambleToLine, filename),
+		newSectionedTemplateFromLines(rawTemplateLines, selectedRequestBlock.fromLine, selectedRequestBlock.toLine, filename+disk.RequestNameSeparator+selectedRequestBlock.name),
+	}, ""
+}
+
+// ListRequests returns the template file names with any named
+// requests selected (file.ain#name), one per request in the file
+func ListRequests(filenames []string) ([]string, error) {
+	requestFilenames := []string{}
+
+	for _, filename := range filenames {
+		rawTemplateString, err := disk.ReadRawTemplateString(filename, false)
+		if err != nil {
+			return nil, err
+		}
+
+		rawTemplateLines := strings.Split(strings.ReplaceAll(rawTemplateString, "\r\n", "\n"), "\n")
+		_, requestBlocks := splitRequestBlocks(rawTemplateLines)
+
+		if len(requestBlocks) == 0 {
+			requestFilenames = append(requestFilenames, filename)
+			continue
+		}
+
+		for _, requestName := range getRequestNames(requestBlocks) {
+			requestFilenames = append(requestFilenames, filename+disk.RequestNameSeparator+requestName)
+		}
+	}
+
+	return requestFilenames, nil
+}
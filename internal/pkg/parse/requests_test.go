@@ -0,0 +1,80 @@
+package parse
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_splitRequestBlocks(t *testing.T) {
+	tests := map[string]struct {
+		inputTemplate          string
+		expectedPreambleToLine int
+		expectedRequestBlocks  []requestBlock
+	}{
+		"No requests is all preamble": {
+			inputTemplate:          "[Host]\n# ## not a request\nlocalhost",
+			expectedPreambleToLine: 3,
+			expectedRequestBlocks:  []requestBlock{},
+		},
+		"Preamble and two requests": {
+			inputTemplate:          "[Backend]\ncurl\n### get\n[Host]\n  ###   post  \n[Method]",
+			expectedPreambleToLine: 2,
+			expectedRequestBlocks: []requestBlock{
+				{name: "get", separatorLine: 2, fromLine: 3, toLine: 4},
+				{name: "post", separatorLine: 4, fromLine: 5, toLine: 6},
+			},
+		},
+		"Escaped separator and trailing comment is not a request": {
+			inputTemplate:          "`### get\n### get # comment\n### two words",
+			expectedPreambleToLine: 3,
+			expectedRequestBlocks:  []requestBlock{},
+		},
+	}
+
+	for name, test := range tests {
+		preambleToLine, requestBlocks := splitRequestBlocks(strings.Split(test.inputTemplate, "\n"))
+
+		if preambleToLine != test.expectedPreambleToLine {
+			t.Errorf("Test: %s. Expected preamble to line %d, got: %d", name, test.expectedPreambleToLine, preambleToLine)
+		}
+
+		if !reflect.DeepEqual(requestBlocks, test.expectedRequestBlocks) {
+			t.Errorf("Test: %s. Expected %v, got: %v", name, test.expectedRequestBlocks, requestBlocks)
+		}
+	}
+}
+
+func Test_getRequestTemplates(t *testing.T) {
+	rawTemplate := "### Users\n[Host]\nlocalhost\n### get\n[Method]\nGET"
+
+	tests := map[string]struct {
+		requestName       string
+		expectedTemplates []string
+	}{
+		"No request selected is the whole file": {
+			expectedTemplates: []string{"users.ain"},
+		},
+		"Selected request and preamble": {
+			requestName:       "get",
+			expectedTemplates: []string{"users.ain", "users.ain#get"},
+		},
+	}
+
+	for name, test := range tests {
+		requestTemplates, fatal := getRequestTemplates(rawTemplate, "users.ain", test.requestName)
+		if fatal != "" {
+			t.Errorf("Test: %s. Unexpected fatal: %s", name, fatal)
+			continue
+		}
+
+		filenames := []string{}
+		for _, requestTemplate := range requestTemplates {
+			filenames = append(filenames, requestTemplate.filename)
+		}
+
+		if !reflect.DeepEqual(filenames, test.expectedTemplates) {
+			t.Errorf("Test: %s. Expected %v, got: %v", name, test.expectedTemplates, filenames)
+		}
+	}
+}
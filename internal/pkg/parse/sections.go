@@ -43,6 +43,11 @@ const (
 	bodySection           = "[body]"
 	backendSection        = "[backend]"
 	backendOptionsSection = "[backendoptions]"
+	includeSection        = "[include]"
+	authSection           = "[auth]"
+	oauth2Section         = "[oauth2]"
+	signSection           = "[sign]"
+	cookiesSection        = "[cookies]"
 	// As above, so below
 	// If you add one here then add it to the slice below.
 	// AND IF
@@ -60,6 +65,11 @@ var allSectionHeaders = []string{
 	bodySection,
 	backendSection,
 	backendOptionsSection,
+	includeSection,
+	authSection,
+	oauth2Section,
+	signSection,
+	cookiesSection,
 }
 
 var sectionsAllowingExecutables = []string{
@@ -70,6 +80,10 @@ var sectionsAllowingExecutables = []string{
 	bodySection,
 	backendSection,
 	backendOptionsSection,
+	authSection,
+	oauth2Section,
+	signSection,
+	cookiesSection,
 }
 
 type sectionedTemplate struct {
@@ -81,7 +95,10 @@ type sectionedTemplate struct {
 	rawTemplateLines      []string
 
 	filename string
-	fatals   []string
+	// The templates (outermost first) that included this
+	// template via [Include], empty if given on the command line
+	includeChain string
+	fatals       []string
 }
 
 func (s *sectionedTemplate) getNamedSection(sectionHeader string) *[]sourceMarker {
@@ -218,10 +235,17 @@ func (s *sectionedTemplate) expandTemplateLines(
 func newSectionedTemplate(rawTemplateString, filename string) *sectionedTemplate {
 	rawTemplateLines := strings.Split(strings.ReplaceAll(rawTemplateString, "\r\n", "\n"), "\n")
 
+	return newSectionedTemplateFromLines(rawTemplateLines, 0, len(rawTemplateLines), filename)
+}
+
+// newSectionedTemplateFromLines makes a template of the raw lines between
+// fromLine and toLine. All raw lines are kept so fatals show the line
+// numbers and context of the whole file.
+func newSectionedTemplateFromLines(rawTemplateLines []string, fromLine, toLine int, filename string) *sectionedTemplate {
 	expandedTemplateLines := []expandedSourceMarker{}
 
-	for sourceIndex, rawTemplateLine := range rawTemplateLines {
-		content, comment := splitTextOnComment(rawTemplateLine)
+	for sourceIndex := fromLine; sourceIndex < toLine; sourceIndex++ {
+		content, comment := splitTextOnComment(rawTemplateLines[sourceIndex])
 
 		expandedTemplateLines = append(expandedTemplateLines, expandedSourceMarker{
 			content:      content,
@@ -0,0 +1,187 @@
+package parse
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+	"github.com/jonaslu/ain/internal/pkg/sign"
+	"github.com/jonaslu/ain/internal/pkg/utils"
+)
+
+var signTypes = []string{data.SignAWSSigV4, data.SignHMAC}
+
+// Keys in the [Sign] section (lowercased) and the sign type using them
+var signKeys = map[string]string{
+	"type":      "",
+	"service":   data.SignAWSSigV4,
+	"region":    data.SignAWSSigV4,
+	"key":       data.SignHMAC,
+	"algorithm": data.SignHMAC,
+	"header":    data.SignHMAC,
+	"prefix":    data.SignHMAC,
+	"encoding":  data.SignHMAC,
+	"parts":     data.SignHMAC,
+}
+
+var signKeyNames = []string{"Type", "Service", "Region", "Key", "Algorithm", "Header", "Prefix", "Encoding", "Parts"}
+
+func getSignSuggestion(value string, validValues []string) string {
+	for _, validValue := range validValues {
+		if utils.LevenshteinDistance(strings.ToLower(value), strings.ToLower(validValue)) < 3 {
+			return validValue
+		}
+	}
+
+	return ""
+}
+
+func getUnknownSignValueFatal(name, value string, validValues []string) string {
+	if suggestion := getSignSuggestion(value, validValues); suggestion != "" {
+		return fmt.Sprintf("Unknown [Sign] %s: %s. Did you mean %s", name, value, suggestion)
+	}
+
+	return fmt.Sprintf("Unknown [Sign] %s %s, valid values are: %s", name, value, strings.Join(validValues, ", "))
+}
+
+func isSignValue(value string, validValues []string) bool {
+	for _, validValue := range validValues {
+		if value == validValue {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getSignHMACPartFatal(part string) string {
+	if strings.HasPrefix(part, sign.HMACHeaderPartPrefix) {
+		if strings.TrimPrefix(part, sign.HMACHeaderPartPrefix) == "" {
+			return "[Sign] part header: needs a header name, e g header:X-Timestamp"
+		}
+
+		return ""
+	}
+
+	if isSignValue(part, sign.HMACParts) {
+		return ""
+	}
+
+	return getUnknownSignValueFatal("part", part, append(sign.HMACParts, sign.HMACHeaderPartPrefix+"<name>"))
+}
+
+// Lines are trimmed, so a prefix ending with a
+// space (e g "HMAC ") must be quoted
+func unquoteSignValue(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+
+	return value
+}
+
+func (s *sectionedTemplate) getSign() *data.Sign {
+	signSourceMarkers := *s.getNamedSection(signSection)
+	if len(signSourceMarkers) == 0 {
+		return nil
+	}
+
+	templateSign := data.Sign{}
+	signKeyLines := map[string]int{}
+	// Original key names in the order they're set
+	signKeyNamesSet := []string{}
+
+	for _, signSourceMarker := range signSourceMarkers {
+		keyValueMatch := keyValueLineRe.FindStringSubmatch(signSourceMarker.lineContents)
+		if keyValueMatch == nil {
+			s.setFatalMessage("[Sign] lines must be <key>=<value>", signSourceMarker.sourceLineIndex)
+			continue
+		}
+
+		key := strings.ToLower(keyValueMatch[1])
+		value := keyValueMatch[2]
+
+		if _, exists := signKeys[key]; !exists {
+			s.setFatalMessage(getUnknownSignValueFatal("key", keyValueMatch[1], signKeyNames), signSourceMarker.sourceLineIndex)
+			continue
+		}
+
+		if _, exists := signKeyLines[key]; exists {
+			s.setFatalMessage(fmt.Sprintf("[Sign] key %s set twice", keyValueMatch[1]), signSourceMarker.sourceLineIndex)
+			continue
+		}
+
+		signKeyLines[key] = signSourceMarker.sourceLineIndex
+		signKeyNamesSet = append(signKeyNamesSet, keyValueMatch[1])
+
+		switch key {
+		case "type":
+			templateSign.Type = strings.ToLower(value)
+			if !isSignValue(templateSign.Type, signTypes) {
+				s.setFatalMessage(getUnknownSignValueFatal("type", value, signTypes), signSourceMarker.sourceLineIndex)
+			}
+		case "service":
+			templateSign.Service = value
+		case "region":
+			templateSign.Region = value
+		case "key":
+			templateSign.Key = value
+		case "algorithm":
+			templateSign.Algorithm = strings.ToLower(value)
+			if !isSignValue(templateSign.Algorithm, sign.HMACAlgorithmNames()) {
+				s.setFatalMessage(getUnknownSignValueFatal("algorithm", value, sign.HMACAlgorithmNames()), signSourceMarker.sourceLineIndex)
+			}
+		case "header":
+			templateSign.Header = value
+		case "prefix":
+			templateSign.Prefix = unquoteSignValue(value)
+		case "encoding":
+			templateSign.Encoding = strings.ToLower(value)
+			if !isSignValue(templateSign.Encoding, sign.HMACEncodings) {
+				s.setFatalMessage(getUnknownSignValueFatal("encoding", value, sign.HMACEncodings), signSourceMarker.sourceLineIndex)
+			}
+		case "parts":
+			templateSign.Parts = strings.Fields(strings.ToLower(value))
+			for _, part := range templateSign.Parts {
+				if partFatal := getSignHMACPartFatal(part); partFatal != "" {
+					s.setFatalMessage(partFatal, signSourceMarker.sourceLineIndex)
+				}
+			}
+		}
+	}
+
+	if s.hasFatalMessages() {
+		return nil
+	}
+
+	firstSignLine := signSourceMarkers[0].sourceLineIndex
+
+	if templateSign.Type == "" {
+		s.setFatalMessage(fmt.Sprintf("[Sign] is missing mandatory Type, valid types are: %s", strings.Join(signTypes, ", ")), firstSignLine)
+		return nil
+	}
+
+	for _, keyName := range signKeyNamesSet {
+		key := strings.ToLower(keyName)
+		if signType := signKeys[key]; signType != "" && signType != templateSign.Type {
+			s.setFatalMessage(fmt.Sprintf("[Sign] key %s is not used by type %s", keyName, templateSign.Type), signKeyLines[key])
+		}
+	}
+
+	switch templateSign.Type {
+	case data.SignAWSSigV4:
+		if templateSign.Service == "" || templateSign.Region == "" {
+			s.setFatalMessage("[Sign] type aws-sigv4 needs both Service and Region", firstSignLine)
+		}
+	case data.SignHMAC:
+		if templateSign.Key == "" {
+			s.setFatalMessage("[Sign] type hmac needs a Key", firstSignLine)
+		}
+	}
+
+	if s.hasFatalMessages() {
+		return nil
+	}
+
+	return &templateSign
+}
@@ -0,0 +1,34 @@
+package parse
+
+import (
+	"net/url"
+	"strings"
+)
+
+// A [Host] such as http+unix:///var/run/docker.sock:/v1.43/info calls
+// the path after the colon over the unix socket before it
+const unixSocketScheme = "http+unix"
+
+// Host used in the url when calling over a unix socket
+const unixSocketHost = "localhost"
+
+func isUnixSocketHost(hostUrl *url.URL) bool {
+	return strings.EqualFold(hostUrl.Scheme, unixSocketScheme)
+}
+
+// splitUnixSocketHost returns the socket path and the
+// http url to call over it
+func splitUnixSocketHost(hostUrl *url.URL) (string, *url.URL) {
+	socketPath, urlPath, _ := strings.Cut(hostUrl.Host+hostUrl.Path, ":")
+	if urlPath == "" {
+		urlPath = "/"
+	}
+
+	return socketPath, &url.URL{
+		Scheme:   "http",
+		Host:     unixSocketHost,
+		Path:     urlPath,
+		RawQuery: hostUrl.RawQuery,
+		Fragment: hostUrl.Fragment,
+	}
+}
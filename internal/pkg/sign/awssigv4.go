@@ -0,0 +1,210 @@
+package sign
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+	"github.com/pkg/errors"
+)
+
+const (
+	awsAlgorithm     = "AWS4-HMAC-SHA256"
+	awsDateFormat    = "20060102"
+	awsTimeFormat    = "20060102T150405Z"
+	awsRequestSuffix = "aws4_request"
+)
+
+type awsCredentials struct {
+	accessKeyId     string
+	secretAccessKey string
+	sessionToken    string
+}
+
+func getAWSCredentials() (awsCredentials, error) {
+	credentials := awsCredentials{
+		accessKeyId:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+
+	if credentials.accessKeyId == "" || credentials.secretAccessKey == "" {
+		return credentials, errors.New("aws-sigv4 needs the environment variables AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+
+	return credentials, nil
+}
+
+// awsURIEncode encodes everything except the unreserved
+// characters as the signature v4 spec requires
+func awsURIEncode(value string, encodeSlash bool) string {
+	var encoded strings.Builder
+
+	for _, b := range []byte(value) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9', b == '-', b == '_', b == '.', b == '~':
+			encoded.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			encoded.WriteByte(b)
+		default:
+			fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+
+	return encoded.String()
+}
+
+func getAWSCanonicalURI(backendInput *data.BackendInput) string {
+	path := backendInput.Host.Path
+	if path == "" {
+		return "/"
+	}
+
+	canonicalURI := awsURIEncode(path, false)
+
+	// All services but S3 encode the path twice
+	if backendInput.Sign.Service != "s3" {
+		canonicalURI = awsURIEncode(canonicalURI, false)
+	}
+
+	return canonicalURI
+}
+
+func getAWSCanonicalQuery(backendInput *data.BackendInput) string {
+	query, err := url.ParseQuery(backendInput.Host.RawQuery)
+	if err != nil {
+		return backendInput.Host.RawQuery
+	}
+
+	// Sorted by encoded key and then by encoded value. Sorting the joined
+	// key=value would put max-items before max, since - sorts before =.
+	queryParams := [][2]string{}
+	for key, values := range query {
+		for _, value := range values {
+			queryParams = append(queryParams, [2]string{awsURIEncode(key, true), awsURIEncode(value, true)})
+		}
+	}
+
+	sort.Slice(queryParams, func(i, j int) bool {
+		if queryParams[i][0] != queryParams[j][0] {
+			return queryParams[i][0] < queryParams[j][0]
+		}
+
+		return queryParams[i][1] < queryParams[j][1]
+	})
+
+	canonicalQueryParams := []string{}
+	for _, queryParam := range queryParams {
+		canonicalQueryParams = append(canonicalQueryParams, queryParam[0]+"="+queryParam[1])
+	}
+
+	return strings.Join(canonicalQueryParams, "&")
+}
+
+// getAWSCanonicalHeaders returns the canonical headers and the
+// names of the signed headers. All headers are signed.
+func getAWSCanonicalHeaders(headers []header) (string, string) {
+	headerValues := map[string][]string{}
+
+	for _, header := range headers {
+		name := strings.ToLower(header.name)
+		headerValues[name] = append(headerValues[name], strings.Join(strings.Fields(header.value), " "))
+	}
+
+	headerNames := []string{}
+	for name := range headerValues {
+		headerNames = append(headerNames, name)
+	}
+
+	sort.Strings(headerNames)
+
+	canonicalHeaders := ""
+	for _, name := range headerNames {
+		canonicalHeaders += name + ":" + strings.Join(headerValues[name], ",") + "\n"
+	}
+
+	return canonicalHeaders, strings.Join(headerNames, ";")
+}
+
+func hmacSHA256(key []byte, value string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+
+	return mac.Sum(nil)
+}
+
+func sha256Hex(value string) string {
+	hash := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(hash[:])
+}
+
+// getAWSSigV4Headers returns the headers signing the request with AWS
+// signature version 4, see https://docs.aws.amazon.com/IAM/latest/UserGuide/create-signed-request.html
+func getAWSSigV4Headers(backendInput *data.BackendInput, now time.Time) ([]string, error) {
+	credentials, err := getAWSCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	sign := backendInput.Sign
+	now = now.UTC()
+	amzDate := now.Format(awsTimeFormat)
+	bodyHash := sha256Hex(backendInput.GetBody())
+
+	signatureHeaders := []string{"X-Amz-Date: " + amzDate}
+	if credentials.sessionToken != "" {
+		signatureHeaders = append(signatureHeaders, "X-Amz-Security-Token: "+credentials.sessionToken)
+	}
+
+	if sign.Service == "s3" {
+		signatureHeaders = append(signatureHeaders, "X-Amz-Content-Sha256: "+bodyHash)
+	}
+
+	headers := getHeaders(append(append([]string{}, backendInput.Headers...), signatureHeaders...))
+	if _, found := getHeaderValue(headers, "Host"); !found {
+		headers = append(headers, header{name: "Host", value: backendInput.Host.Host})
+	}
+
+	canonicalHeaders, signedHeaders := getAWSCanonicalHeaders(headers)
+
+	canonicalRequest := strings.Join([]string{
+		backendInput.GetMethod(),
+		getAWSCanonicalURI(backendInput),
+		getAWSCanonicalQuery(backendInput),
+		canonicalHeaders,
+		signedHeaders,
+		bodyHash,
+	}, "\n")
+
+	credentialScope := strings.Join([]string{now.Format(awsDateFormat), sign.Region, sign.Service, awsRequestSuffix}, "/")
+
+	stringToSign := strings.Join([]string{
+		awsAlgorithm,
+		amzDate,
+		credentialScope,
+		sha256Hex(canonicalRequest),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+credentials.secretAccessKey), now.Format(awsDateFormat))
+	signingKey = hmacSHA256(signingKey, sign.Region)
+	signingKey = hmacSHA256(signingKey, sign.Service)
+	signingKey = hmacSHA256(signingKey, awsRequestSuffix)
+
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	authorization := fmt.Sprintf("Authorization: %s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		awsAlgorithm,
+		credentials.accessKeyId,
+		credentialScope,
+		signedHeaders,
+		signature)
+
+	return append(signatureHeaders, authorization), nil
+}
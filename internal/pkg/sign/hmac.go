@@ -0,0 +1,132 @@
+package sign
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"sort"
+	"strings"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultHMACAlgorithm = "sha256"
+	defaultHMACHeader    = "X-Signature"
+	defaultHMACEncoding  = "hex"
+)
+
+// Signs the method, the whole url and the body if no parts are given
+var defaultHMACParts = []string{"method", "url", "body"}
+
+// HMACParts are the parts of the request that can go into
+// the signature. A header is given as header:<name>.
+var HMACParts = []string{"method", "url", "host", "path", "query", "body"}
+
+const HMACHeaderPartPrefix = "header:"
+
+var HMACEncodings = []string{"hex", "base64"}
+
+var hmacAlgorithms = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// HMACAlgorithmNames returns the hmac algorithms sorted by name
+func HMACAlgorithmNames() []string {
+	algorithmNames := []string{}
+	for algorithmName := range hmacAlgorithms {
+		algorithmNames = append(algorithmNames, algorithmName)
+	}
+
+	sort.Strings(algorithmNames)
+
+	return algorithmNames
+}
+
+func getHMACPart(backendInput *data.BackendInput, headers []header, part string) (string, error) {
+	switch part {
+	case "method":
+		return backendInput.GetMethod(), nil
+	case "url":
+		return backendInput.Host.String(), nil
+	case "host":
+		return backendInput.Host.Host, nil
+	case "path":
+		return getPath(backendInput), nil
+	case "query":
+		return backendInput.Host.RawQuery, nil
+	case "body":
+		return backendInput.GetBody(), nil
+	}
+
+	if strings.HasPrefix(part, HMACHeaderPartPrefix) {
+		headerName := strings.TrimPrefix(part, HMACHeaderPartPrefix)
+		if headerValue, found := getHeaderValue(headers, headerName); found {
+			return headerValue, nil
+		}
+
+		return "", errors.Errorf("hmac part %s has no matching header in [Headers]", part)
+	}
+
+	return "", errors.Errorf("unknown hmac part %s", part)
+}
+
+// getHMACHeaders returns a header with the hmac of the
+// request parts given, each part separated by a newline
+func getHMACHeaders(backendInput *data.BackendInput) ([]string, error) {
+	sign := backendInput.Sign
+
+	algorithm := sign.Algorithm
+	if algorithm == "" {
+		algorithm = defaultHMACAlgorithm
+	}
+
+	newHash, exists := hmacAlgorithms[algorithm]
+	if !exists {
+		return nil, errors.Errorf("unknown hmac algorithm %s", algorithm)
+	}
+
+	parts := sign.Parts
+	if len(parts) == 0 {
+		parts = defaultHMACParts
+	}
+
+	headers := getHeaders(backendInput.Headers)
+
+	partValues := []string{}
+	for _, part := range parts {
+		partValue, err := getHMACPart(backendInput, headers, part)
+		if err != nil {
+			return nil, err
+		}
+
+		partValues = append(partValues, partValue)
+	}
+
+	mac := hmac.New(newHash, []byte(sign.Key))
+	mac.Write([]byte(strings.Join(partValues, "\n")))
+
+	var signature string
+	switch sign.Encoding {
+	case "base64":
+		signature = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	case "", defaultHMACEncoding:
+		signature = hex.EncodeToString(mac.Sum(nil))
+	default:
+		return nil, errors.Errorf("unknown hmac encoding %s", sign.Encoding)
+	}
+
+	headerName := sign.Header
+	if headerName == "" {
+		headerName = defaultHMACHeader
+	}
+
+	return []string{headerName + ": " + sign.Prefix + signature}, nil
+}
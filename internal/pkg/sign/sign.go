@@ -0,0 +1,86 @@
+package sign
+
+import (
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+	"github.com/pkg/errors"
+)
+
+type header struct {
+	name  string
+	value string
+}
+
+// getHeaders splits the [Headers] lines into name and value.
+// Lines without a colon are left out since they're not headers.
+func getHeaders(headerLines []string) []header {
+	headers := []header{}
+
+	for _, headerLine := range headerLines {
+		name, value, found := strings.Cut(headerLine, ":")
+		if !found {
+			continue
+		}
+
+		headers = append(headers, header{name: strings.TrimSpace(name), value: strings.TrimSpace(value)})
+	}
+
+	return headers
+}
+
+func getHeaderValue(headers []header, name string) (string, bool) {
+	for _, header := range headers {
+		if textproto.CanonicalMIMEHeaderKey(header.name) == textproto.CanonicalMIMEHeaderKey(name) {
+			return header.value, true
+		}
+	}
+
+	return "", false
+}
+
+func getPath(backendInput *data.BackendInput) string {
+	if path := backendInput.Host.EscapedPath(); path != "" {
+		return path
+	}
+
+	return "/"
+}
+
+// SignRequest adds headers with the signature of the assembled request.
+// It runs after the templates are assembled since the signature covers
+// the final method, url, headers and body.
+func SignRequest(backendInput *data.BackendInput, now time.Time) error {
+	if backendInput.Sign == nil {
+		return nil
+	}
+
+	// The whole body is needed to sign it
+	if backendInput.BodyFromStdin {
+		if err := backendInput.ReadBodyFromStdin(); err != nil {
+			return err
+		}
+	}
+
+	var signatureHeaders []string
+	var err error
+
+	switch backendInput.Sign.Type {
+	case data.SignAWSSigV4:
+		signatureHeaders, err = getAWSSigV4Headers(backendInput, now)
+	case data.SignHMAC:
+		signatureHeaders, err = getHMACHeaders(backendInput)
+	default:
+		err = errors.Errorf("unknown sign type %s", backendInput.Sign.Type)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "could not sign request")
+	}
+
+	backendInput.Headers = append(backendInput.Headers, signatureHeaders...)
+
+	return nil
+}
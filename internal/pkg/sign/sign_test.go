@@ -0,0 +1,118 @@
+package sign
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jonaslu/ain/internal/pkg/data"
+)
+
+func Test_SignRequest(t *testing.T) {
+	// The aws-sigv4 tests are from the AWS signature v4 test suite
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+
+	awsSigV4 := &data.Sign{Type: data.SignAWSSigV4, Service: "service", Region: "us-east-1"}
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		host            string
+		method          string
+		headers         []string
+		body            []string
+		sign            *data.Sign
+		expectedHeaders []string
+	}{
+		"aws-sigv4 get-vanilla": {
+			host: "https://example.amazonaws.com/",
+			sign: awsSigV4,
+			expectedHeaders: []string{
+				"X-Amz-Date: 20150830T123600Z",
+				"Authorization: AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+			},
+		},
+		"aws-sigv4 get-vanilla-query-order-key-case": {
+			host: "https://example.amazonaws.com/?Param2=value2&Param1=value1",
+			sign: awsSigV4,
+			expectedHeaders: []string{
+				"X-Amz-Date: 20150830T123600Z",
+				"Authorization: AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+			},
+		},
+		"aws-sigv4 post-vanilla": {
+			host:   "https://example.amazonaws.com/",
+			method: "post",
+			sign:   awsSigV4,
+			expectedHeaders: []string{
+				"X-Amz-Date: 20150830T123600Z",
+				"Authorization: AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b",
+			},
+		},
+		"hmac defaults to hex sha256 of method, url and body": {
+			host: "http://localhost:8080/api",
+			body: []string{"{", `  "a": 1`, "}"},
+			sign: &data.Sign{Type: data.SignHMAC, Key: "key"},
+			// printf 'POST\nhttp://localhost:8080/api\n{\n  "a": 1\n}' | openssl dgst -sha256 -hmac key
+			expectedHeaders: []string{"X-Signature: 73b1dadfcc49fdb0110df4ed209f8d08181a9bd7a4e2ab78a73d61ad47f41508"},
+		},
+		"hmac with header part, base64 and prefix": {
+			host:    "http://localhost:8080/api?a=1",
+			headers: []string{"X-Timestamp: 1700000000"},
+			sign: &data.Sign{
+				Type:      data.SignHMAC,
+				Key:       "key",
+				Algorithm: "sha1",
+				Header:    "Authorization",
+				Prefix:    "HMAC ",
+				Encoding:  "base64",
+				Parts:     []string{"method", "path", "query", "header:x-timestamp"},
+			},
+			// printf 'GET\n/api\na=1\n1700000000' | openssl dgst -sha1 -hmac key -binary | base64
+			expectedHeaders: []string{"X-Timestamp: 1700000000", "Authorization: HMAC k4Ech56Nmr9nd+hThAbXn277gjo="},
+		},
+	}
+
+	for name, test := range tests {
+		host, _ := url.Parse(test.host)
+
+		backendInput := &data.BackendInput{
+			Host:    host,
+			Method:  test.method,
+			Headers: test.headers,
+			Body:    test.body,
+			Sign:    test.sign,
+		}
+
+		if err := SignRequest(backendInput, now); err != nil {
+			t.Errorf("Test: %s. Unexpected error: %v", name, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(backendInput.Headers, test.expectedHeaders) {
+			t.Errorf("Test: %s. Expected headers:\n%v\ngot:\n%v", name, test.expectedHeaders, backendInput.Headers)
+		}
+	}
+}
+
+func Test_getAWSCanonicalQuery(t *testing.T) {
+	tests := map[string]struct {
+		rawQuery string
+		expected string
+	}{
+		"Key prefix of another key":   {rawQuery: "max-items=1&max=2", expected: "max=2&max-items=1"},
+		"Key prefix ending in number": {rawQuery: "a1=1&a=2", expected: "a=2&a1=1"},
+		"Same key sorted by value":    {rawQuery: "a=b&a=a%20b", expected: "a=a%20b&a=b"},
+		"Keys and values encoded":     {rawQuery: "b=c d&a*=~", expected: "a%2A=~&b=c%20d"},
+	}
+
+	for name, test := range tests {
+		backendInput := &data.BackendInput{Host: &url.URL{RawQuery: test.rawQuery}}
+
+		if canonicalQuery := getAWSCanonicalQuery(backendInput); canonicalQuery != test.expected {
+			t.Errorf("Test: %s, Expected: %s, Got: %s", name, test.expected, canonicalQuery)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+)
+
+// Shell is how a printed command is quoted and
+// continued over several lines for the shell it's pasted into
+type Shell struct {
+	quote            func(string) string
+	lineContinuation string
+	// Commands the shell has taken the name of
+	commandNames map[string]string
+
+	// Can read a here-document in a command substitution
+	HereDocuments bool
+	// Has printf and pipes its output as is
+	PipesPrintf bool
+	// A quoted argument can span several lines
+	MultilineArguments bool
+}
+
+const DefaultShell = "bash"
+
+var posixShell = Shell{
+	quote:              quotePosix,
+	lineContinuation:   " \\\n  ",
+	HereDocuments:      true,
+	PipesPrintf:        true,
+	MultilineArguments: true,
+}
+
+var Shells = map[string]Shell{
+	"sh":   posixShell,
+	"bash": posixShell,
+	"zsh":  posixShell,
+	"fish": {
+		quote:              quoteFish,
+		lineContinuation:   " \\\n  ",
+		PipesPrintf:        true,
+		MultilineArguments: true,
+	},
+	"powershell": {
+		quote:            quotePowershell,
+		lineContinuation: " `\n  ",
+		// Windows PowerShell 5.1 has curl and wget as aliases for Invoke-WebRequest
+		commandNames:       map[string]string{"curl": "curl.exe", "wget": "wget.exe"},
+		MultilineArguments: true,
+	},
+	"cmd": {
+		quote:            quoteCmd,
+		lineContinuation: " ^\n  ",
+	},
+}
+
+// ShellNames returns the shells sorted by name
+func ShellNames() []string {
+	shellNames := []string{}
+	for shellName := range Shells {
+		shellNames = append(shellNames, shellName)
+	}
+
+	sort.Strings(shellNames)
+
+	return shellNames
+}
+
+// GetShell returns the shell by name, the default shell
+// if it's not set. Shell names are checked when parsing the flag.
+func GetShell(shellName string) Shell {
+	if shell, exists := Shells[shellName]; exists {
+		return shell
+	}
+
+	return Shells[DefaultShell]
+}
+
+// Single quotes keep everything as is, a single quote is
+// closed, put in double quotes and opened again
+func quotePosix(unsafeString string) string {
+	return "'" + strings.ReplaceAll(unsafeString, `'`, `'"'"'`) + "'"
+}
+
+// Fish reads \\ and \' in single quotes
+func quoteFish(unsafeString string) string {
+	unsafeString = strings.ReplaceAll(unsafeString, `\`, `\\`)
+	return "'" + strings.ReplaceAll(unsafeString, `'`, `\'`) + "'"
+}
+
+// Powershell doubles a single quote in single quotes,
+// and takes the typographic single quotes as single quotes too
+var powershellSingleQuotes = strings.NewReplacer(
+	"'", "''",
+	"‘", "‘‘",
+	"’", "’’",
+	"‚", "‚‚",
+	"‛", "‛‛",
+)
+
+func quotePowershell(unsafeString string) string {
+	return "'" + powershellSingleQuotes.Replace(unsafeString) + "'"
+}
+
+// Cmd passes the arguments on as one line, which the program splits
+// the way the C runtime does: "" is a quote in a quoted argument and
+// backslashes before a quote are doubled. A % is put outside the quotes
+// escaped with ^ so cmd doesn't expand %variables%.
+func quoteCmd(unsafeString string) string {
+	var builder strings.Builder
+	backslashes := 0
+
+	writeBeforeQuote := func(text string) {
+		builder.WriteString(strings.Repeat(`\`, backslashes*2))
+		builder.WriteString(text)
+		backslashes = 0
+	}
+
+	builder.WriteString(`"`)
+
+	for _, unsafeRune := range unsafeString {
+		switch unsafeRune {
+		case '\\':
+			backslashes++
+		case '"':
+			writeBeforeQuote(`""`)
+		case '%':
+			writeBeforeQuote(`"^%"`)
+		default:
+			builder.WriteString(strings.Repeat(`\`, backslashes))
+			builder.WriteRune(unsafeRune)
+			backslashes = 0
+		}
+	}
+
+	writeBeforeQuote(`"`)
+
+	return builder.String()
+}
+
+// Escape quotes the string as one argument to the shell
+func (s Shell) Escape(unsafeString string) string {
+	return s.quote(unsafeString)
+}
+
+// CommandName returns the name to run the command by in the shell
+func (s Shell) CommandName(commandName string) string {
+	if shellCommandName, exists := s.commandNames[commandName]; exists {
+		return shellCommandName
+	}
+
+	return commandName
+}
+
+// PrettyPrint puts each group of arguments on a line of its own
+func (s Shell) PrettyPrint(args [][]string) string {
+	output := ""
+
+	for i, arg := range args {
+		if len(arg) == 0 {
+			continue
+		}
+
+		output = output + strings.Join(arg, " ")
+		if i+1 < len(args) {
+			output = output + s.lineContinuation
+		}
+	}
+
+	return output
+}
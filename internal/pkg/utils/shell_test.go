@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestShell_Escape(t *testing.T) {
+	tests := map[string]struct {
+		shellName string
+		unsafe    string
+		expected  string
+	}{
+		"bash empty":                   {"bash", "", `''`},
+		"bash single quote":            {"bash", "it's", `'it'"'"'s'`},
+		"bash variables and commands":  {"bash", "$HOME `id` $(id) \\", "'$HOME `id` $(id) \\'"},
+		"bash newline":                 {"bash", "a\nb", "'a\nb'"},
+		"zsh same as bash":             {"zsh", "it's !", `'it'"'"'s !'`},
+		"fish single quote":            {"fish", "it's", `'it\'s'`},
+		"fish backslashes":             {"fish", `a\b\'`, `'a\\b\\\''`},
+		"fish variables and commands":  {"fish", "$HOME (id)", "'$HOME (id)'"},
+		"powershell single quote":      {"powershell", "it's", `'it''s'`},
+		"powershell typographic quote": {"powershell", "it’s ‘a’", `'it’’s ‘‘a’’'`},
+		"powershell variables":         {"powershell", "$env:HOME `n $(id)", "'$env:HOME `n $(id)'"},
+		"cmd empty":                    {"cmd", "", `""`},
+		"cmd double quote":             {"cmd", `say "hi"`, `"say ""hi"""`},
+		"cmd variables":                {"cmd", "%PATH%", `""^%"PATH"^%""`},
+		"cmd operators":                {"cmd", "a & b | c > d ^ e", `"a & b | c > d ^ e"`},
+		"cmd trailing backslash":       {"cmd", `C:\dir\`, `"C:\dir\\"`},
+		"cmd backslash before quote":   {"cmd", `a\"b`, `"a\\""b"`},
+	}
+
+	for name, test := range tests {
+		if got := Shells[test.shellName].Escape(test.unsafe); got != test.expected {
+			t.Errorf("Test: %s, Expected: %s, Got: %s", name, test.expected, got)
+		}
+	}
+}
+
+func TestShell_PrettyPrint(t *testing.T) {
+	args := [][]string{{"-X", "'POST'"}, {}, {"'http://localhost'"}}
+
+	tests := map[string]struct {
+		shellName string
+		expected  string
+	}{
+		"bash":       {"bash", "-X 'POST' \\\n  'http://localhost'"},
+		"fish":       {"fish", "-X 'POST' \\\n  'http://localhost'"},
+		"powershell": {"powershell", "-X 'POST' `\n  'http://localhost'"},
+		"cmd":        {"cmd", "-X 'POST' ^\n  'http://localhost'"},
+	}
+
+	for name, test := range tests {
+		if got := Shells[test.shellName].PrettyPrint(args); got != test.expected {
+			t.Errorf("Test: %s, Expected: %q, Got: %q", name, test.expected, got)
+		}
+	}
+}
+
+func TestShell_CommandName(t *testing.T) {
+	tests := map[string]struct {
+		shellName   string
+		commandName string
+		expected    string
+	}{
+		"bash curl":       {"bash", "curl", "curl"},
+		"powershell curl": {"powershell", "curl", "curl.exe"},
+		"powershell wget": {"powershell", "wget", "wget.exe"},
+		"powershell http": {"powershell", "http", "http"},
+		"cmd curl":        {"cmd", "curl", "curl"},
+	}
+
+	for name, test := range tests {
+		if got := Shells[test.shellName].CommandName(test.commandName); got != test.expected {
+			t.Errorf("Test: %s, Expected: %s, Got: %s", name, test.expected, got)
+		}
+	}
+}
+
+func TestGetShell(t *testing.T) {
+	if got := GetShell(""); got.lineContinuation != Shells[DefaultShell].lineContinuation || !got.HereDocuments {
+		t.Errorf("Test: Not set, Expected: %s, Got: %+v", DefaultShell, got)
+	}
+}
@@ -21,9 +21,11 @@ type testDirectives struct {
 	Env       []string
 	Args      []string
 	AfterArgs []string `yaml:"afterargs"`
-	Stderr    string
-	Stdout    string
-	ExitCode  int
+	// Empty (stdin: "") is an empty pipe
+	Stdin    *string
+	Stderr   string
+	Stdout   string
+	ExitCode int
 }
 
 func addBarsBeforeNewlines(s string) string {
@@ -104,6 +106,10 @@ func runTest(filename string, templateContents []byte) error {
 		cmd.Env = append(cmd.Env, "GOCOVERDIR="+os.Getenv("E2EGOCOVERDIR"))
 	}
 
+	if testDirectives.Stdin != nil {
+		cmd.Stdin = strings.NewReader(*testDirectives.Stdin)
+	}
+
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
@@ -184,6 +190,11 @@ func readTestFiles(templateFolder string) ([]string, error) {
 			continue
 		}
 
+		// Templates used by other tests (e g included)
+		if strings.HasPrefix(file.Name(), "_") {
+			continue
+		}
+
 		testFilePaths = append(testFilePaths, fileName)
 
 	}